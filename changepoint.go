@@ -0,0 +1,167 @@
+package gotimeleft
+
+import "time"
+
+type (
+	// pageHinkley detects a lasting change of the rate with the two-sided Page-Hinkley test on the
+	// deviations of each rate from the running mean, relative to that mean so any rate scale works
+	pageHinkley struct {
+		delta     float64
+		threshold float64
+
+		// First rates, whose median starts the mean so an initial burst does not skew it
+		warmup []float64
+		count  int
+		total  float64
+		// Running mean of the rates, bursts capped at twice the mean so they cannot drag it
+		mean float64
+
+		// Cumulative deviations, their extremes, and the rates seen since each extreme
+		up, upMin, upSum       float64
+		upCount                int
+		down, downMax, downSum float64
+		downCount              int
+	}
+)
+
+const (
+	// DefaultChangeDelta is the relative deviation from the mean rate tolerated as noise
+	DefaultChangeDelta = 0.1
+	// DefaultChangeThreshold is the cumulative relative deviation that signals a change of the rate,
+	// reached after about 15 steps when the speed is divided by 4
+	DefaultChangeThreshold = 10.0
+	// minChangeSamples is the number of rates observed before looking for a change
+	minChangeSamples = 5
+)
+
+// SetChangeDetection detects lasting changes of the speed, like a job leaving a cached phase:
+// the speed history of the old regime is discarded and the OnSpeedChange callback is invoked.
+// delta is the relative deviation tolerated as noise (DefaultChangeDelta when not positive) and
+// threshold the cumulative relative deviation that signals a change (DefaultChangeThreshold is a
+// good start, lower reacts faster with more false alarms). A threshold of 0 disables detection.
+func (t *TimeLeft) SetChangeDetection(delta, threshold float64) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if threshold <= 0 {
+		t.changeDetector = nil
+		return t
+	}
+	if delta <= 0 {
+		delta = DefaultChangeDelta
+	}
+	t.changeDetector = &pageHinkley{delta: delta, threshold: threshold}
+	return t
+}
+
+// OnSpeedChange sets the callback invoked when a lasting change of the speed is detected,
+// with the old and new speeds in values per second
+func (t *TimeLeft) OnSpeedChange(fn func(from, to float64)) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onSpeedChange = fn
+	return t
+}
+
+// SpeedChangedAt returns when the last change of the speed was detected, zero if none was
+func (t *TimeLeft) SpeedChangedAt() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.speedChangedAt
+}
+
+// detectSpeedChange feeds the rate of the last step to the detector and, on a change, restarts the
+// speed history at the new rate. It returns the callback to invoke outside the lock, if any.
+func (t *TimeLeft) detectSpeedChange(previousValue int, previousStepTime time.Time) func() {
+	if t.changeDetector == nil || previousStepTime.IsZero() {
+		return nil
+	}
+	elapsed := t.lastStepTime.Sub(previousStepTime).Seconds()
+	if elapsed <= 0 {
+		return nil
+	}
+
+	from, to, changed := t.changeDetector.observe(float64(t.lastValue-previousValue) / elapsed)
+	if !changed {
+		return nil
+	}
+
+	t.speedHistory = make([]float64, 0, t.maxHistorySize)
+	t.speedTimes = nil
+	t.speedPerMicrosecond = to / 1e6
+	t.recordSpeed(t.speedPerMicrosecond, t.lastStepTime)
+	t.speedChangedAt = t.lastStepTime
+	t.resetEstimator(false)
+
+	if fn := t.onSpeedChange; fn != nil {
+		return func() { fn(from, to) }
+	}
+	return nil
+}
+
+// observe adds a rate and returns the mean rates before and after a change when one is detected
+func (p *pageHinkley) observe(rate float64) (from, to float64, changed bool) {
+	p.count++
+	p.total += rate
+	if p.count <= minChangeSamples {
+		p.warmup = append(p.warmup, rate)
+		if p.count == minChangeSamples {
+			p.mean = medianOf(p.warmup)
+			p.warmup = nil
+		}
+		return 0, 0, false
+	}
+	if p.mean <= 0 {
+		p.mean += (rate - p.mean) / float64(p.count)
+		return 0, 0, false
+	}
+
+	// A single burst should not count as much as a sustained change, so increases are capped like decreases
+	capped := rate
+	if capped > 2*p.mean {
+		capped = 2 * p.mean
+	}
+	deviation := (capped - p.mean) / p.mean
+	p.mean += (capped - p.mean) / float64(p.count)
+
+	p.up += deviation - p.delta
+	if p.up < p.upMin {
+		p.upMin, p.upSum, p.upCount = p.up, 0, 0
+	} else {
+		p.upSum += rate
+		p.upCount++
+	}
+
+	p.down += deviation + p.delta
+	if p.down > p.downMax {
+		p.downMax, p.downSum, p.downCount = p.down, 0, 0
+	} else {
+		p.downSum += rate
+		p.downCount++
+	}
+
+	var sum float64
+	var count int
+	switch {
+	case p.up-p.upMin > p.threshold && p.upCount > 0:
+		sum, count = p.upSum, p.upCount
+	case p.downMax-p.down > p.threshold && p.downCount > 0:
+		sum, count = p.downSum, p.downCount
+	default:
+		return 0, 0, false
+	}
+	to = sum / float64(count)
+	from = (p.total - sum) / float64(p.count-count)
+
+	// Start over from the new regime, without a warm-up as its mean is known
+	p.reset()
+	p.count, p.total, p.mean = minChangeSamples, to*minChangeSamples, to
+	return from, to, true
+}
+
+// reset forgets every rate, keeping the configuration
+func (p *pageHinkley) reset() {
+	*p = pageHinkley{delta: p.delta, threshold: p.threshold}
+}
@@ -0,0 +1,103 @@
+package gotimeleft
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// noisyRates returns n rates uniformly spread within ±noise (relative) around rate
+func noisyRates(rnd *rand.Rand, n int, rate, noise float64) []float64 {
+	rates := make([]float64, n)
+	for i := range rates {
+		rates[i] = rate * (1 + noise*(2*rnd.Float64()-1))
+	}
+	return rates
+}
+
+func TestPageHinkley(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   float64
+		after    float64
+		maxSteps int
+	}{
+		{name: "Slower", before: 100, after: 25, maxSteps: 20},
+		{name: "Much slower", before: 1e6, after: 1e4, maxSteps: 15},
+		{name: "Faster", before: 25, after: 100, maxSteps: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			p := &pageHinkley{delta: DefaultChangeDelta, threshold: DefaultChangeThreshold}
+
+			for _, rate := range noisyRates(rnd, 100, tt.before, 0.2) {
+				_, _, changed := p.observe(rate)
+				assert.False(t, changed, "no change before the switch")
+			}
+
+			for _, rate := range noisyRates(rnd, tt.maxSteps, tt.after, 0.2) {
+				from, to, changed := p.observe(rate)
+				if changed {
+					assert.InEpsilon(t, tt.before, from, 0.1)
+					assert.InEpsilon(t, tt.after, to, 0.2)
+					return
+				}
+			}
+			t.Errorf("change not detected within %d steps", tt.maxSteps)
+		})
+	}
+}
+
+func TestPageHinkley_NoFalseAlarm(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	p := &pageHinkley{delta: DefaultChangeDelta, threshold: DefaultChangeThreshold}
+
+	for i, rate := range noisyRates(rnd, 5000, 100, 0.4) {
+		// Occasional bursts, like a buffer being flushed at once
+		if i%50 == 0 {
+			rate *= 20
+		}
+		_, _, changed := p.observe(rate)
+		assert.False(t, changed, "false alarm at step %d", i)
+	}
+}
+
+func TestTimeLeft_OnSpeedChange(t *testing.T) {
+	tl := Init(1000000).SetChangeDetection(0, DefaultChangeThreshold)
+
+	var from, to float64
+	var changedAt time.Time
+	tl.OnSpeedChange(func(f, tt float64) {
+		// Invoked outside the lock, so the tracker can be queried
+		from, to, changedAt = f, tt, tl.SpeedChangedAt()
+	})
+
+	// Feed steps with synthetic timing: 1000 values per second, then 100
+	advance := func(values int, elapsed time.Duration) {
+		tl.update(func() {
+			tl.lastValue += values
+			tl.lastStepTime = tl.lastStepTime.Add(elapsed)
+		})
+	}
+	for i := 0; i < 50; i++ {
+		advance(100, 100*time.Millisecond)
+	}
+	assert.True(t, tl.SpeedChangedAt().IsZero())
+
+	for i := 0; i < 20; i++ {
+		advance(10, 100*time.Millisecond)
+	}
+
+	assert.InEpsilon(t, 1000, from, 0.01)
+	assert.InEpsilon(t, 100, to, 0.01)
+	assert.False(t, changedAt.IsZero())
+	assert.Equal(t, changedAt, tl.Snapshot().SpeedChangedAt)
+	assert.InEpsilon(t, 100, tl.Snapshot().PerSecond, 0.5, "the speed should restart from the new regime")
+
+	tl.Reset(1000000)
+	assert.True(t, tl.SpeedChangedAt().IsZero())
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jonathanhecl/gotimeleft"
+)
+
+const ffmpegUsage = `Usage: ffmpeg -progress pipe:1 -nostats ... | gotimeleft ffmpeg [-duration d | -input file]
+
+Reads the ffmpeg -progress stream from stdin and shows the progress of the media
+time against the input duration, with the speed as a multiple of realtime.
+
+Flags:`
+
+type (
+	// ffmpegOptions are the options of the ffmpeg mode
+	ffmpegOptions struct {
+		duration string
+		input    string
+		quiet    bool
+		interval time.Duration
+		width    int
+	}
+)
+
+// runFFmpeg shows the progress of an ffmpeg -progress stream read from stdin
+func runFFmpeg(args []string) int {
+	fs := flag.NewFlagSet("gotimeleft ffmpeg", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), ffmpegUsage)
+		fs.PrintDefaults()
+	}
+
+	var o ffmpegOptions
+	fs.StringVar(&o.duration, "duration", "", "input duration (01:23:45.67, 83.45 or 1h23m45s)")
+	fs.StringVar(&o.input, "input", "", "input file whose duration is probed with ffprobe")
+	fs.BoolVar(&o.quiet, "q", false, "quiet: do not show any progress")
+	fs.DurationVar(&o.interval, "i", time.Second, "update interval")
+	fs.IntVar(&o.width, "w", 30, "progress bar width")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := o.track(os.Stdin, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+		return 1
+	}
+	return 0
+}
+
+// track reads the progress stream from in, rendering the progress on status
+func (o *ffmpegOptions) track(in io.Reader, status io.Writer) error {
+	duration, err := o.inputDuration()
+	if err != nil {
+		return err
+	}
+	if o.quiet {
+		status = io.Discard
+	}
+
+	// Work units are milliseconds of media time
+	t := gotimeleft.Init(int(duration.Milliseconds()))
+
+	var speed atomic.Value
+	speed.Store(0.0)
+
+	r := newRenderer(status, t, o.interval, o.width, formatMediaTime)
+	r.rate = func(s gotimeleft.Snapshot) string {
+		// Prefer the speed reported by ffmpeg, or derive it from media time per second
+		realtime := speed.Load().(float64)
+		if realtime <= 0 {
+			realtime = s.PerSecond / 1000
+		}
+		return strconv.FormatFloat(realtime, 'f', 2, 64) + "x"
+	}
+	r.start()
+	defer r.finish()
+
+	return gotimeleft.ReadFFmpegProgress(in, func(p gotimeleft.FFmpegProgress) {
+		speed.Store(p.Speed)
+		if p.OutTime > 0 {
+			t.Value(int(p.OutTime.Milliseconds()))
+		}
+		if p.Done {
+			t.Finish()
+		}
+	})
+}
+
+// inputDuration returns the duration from -duration or probed from -input
+func (o *ffmpegOptions) inputDuration() (time.Duration, error) {
+	switch {
+	case o.duration != "":
+		if d, err := gotimeleft.ParseFFmpegTime(o.duration); err == nil {
+			return d, nil
+		}
+		return time.ParseDuration(o.duration)
+	case o.input != "":
+		return probeDuration(o.input)
+	}
+	return 0, errors.New("either -duration or -input is required")
+}
+
+// probeDuration returns the duration of a media file using ffprobe
+func probeDuration(path string) (time.Duration, error) {
+	out, err := exec.Command("ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path,
+	).Output()
+	if err != nil {
+		return 0, fmt.Errorf("probing duration of %s: %w", path, err)
+	}
+	return gotimeleft.ParseFFmpegTime(strings.TrimSpace(string(out)))
+}
+
+// formatMediaTime formats milliseconds of media time (1m23s)
+func formatMediaTime(ms float64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
+}
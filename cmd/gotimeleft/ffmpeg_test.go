@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFFmpegOptions_InputDuration(t *testing.T) {
+
+	tests := []struct {
+		duration string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{duration: "00:01:30.5", want: 90500 * time.Millisecond},
+		{duration: "90.5", want: 90500 * time.Millisecond},
+		{duration: "1m30s", want: 90 * time.Second},
+		{duration: "soon", wantErr: true},
+		{duration: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			o := ffmpegOptions{duration: tt.duration}
+			got, err := o.inputDuration()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFFmpegOptions_Track(t *testing.T) {
+	stream := strings.Join([]string{
+		"out_time=00:00:30.000000", "speed=1.5x", "progress=continue",
+		"out_time=00:01:00.000000", "speed=1.5x", "progress=continue",
+		"out_time=00:01:59.960000", "speed=1.5x", "progress=end",
+	}, "\n")
+
+	var status bytes.Buffer
+	o := ffmpegOptions{duration: "00:02:00", width: 10, interval: time.Hour}
+	assert.NoError(t, o.track(strings.NewReader(stream), &status))
+
+	got := status.String()
+	assert.Contains(t, got, "2m0s/2m0s")
+	assert.Contains(t, got, "1.50x")
+	assert.True(t, strings.HasSuffix(got, "\n"))
+}
+
+func TestFormatMediaTime(t *testing.T) {
+	assert.Equal(t, "1m23s", formatMediaTime(83450))
+	assert.Equal(t, "0s", formatMediaTime(0))
+}
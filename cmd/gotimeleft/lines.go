@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/jonathanhecl/gotimeleft"
+)
+
+// copyLines copies in to out unchanged, stepping t once per newline-delimited record.
+// A last record without a trailing newline is counted too.
+func copyLines(out io.Writer, in io.Reader, t *gotimeleft.TimeLeft) error {
+	r := bufio.NewReaderSize(in, 64*1024)
+	partial := false
+	for {
+		line, err := r.ReadSlice('\n')
+		if len(line) > 0 {
+			if _, err := out.Write(line); err != nil {
+				return err
+			}
+			partial = true
+		}
+		if err == bufio.ErrBufferFull {
+			// Long record: count it once its newline arrives
+			continue
+		}
+		if partial && (err == nil || err == io.EOF) {
+			t.Step(1)
+			partial = false
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// countFileLines returns the number of newline-delimited records in a file,
+// counting a last record without a trailing newline too
+func countFileLines(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	lines, last := 0, byte('\n')
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if last != '\n' {
+		lines++
+	}
+	return lines, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonathanhecl/gotimeleft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyLines(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "Empty", input: "", want: 0},
+		{name: "Single line", input: "one\n", want: 1},
+		{name: "Without trailing newline", input: "one\ntwo", want: 2},
+		{name: "Empty lines", input: "\n\n\n", want: 3},
+		{name: "Long line", input: strings.Repeat("x", 200*1024) + "\nshort\n", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := gotimeleft.Init(0)
+			var out bytes.Buffer
+			assert.NoError(t, copyLines(&out, strings.NewReader(tt.input), tl))
+			assert.Equal(t, tt.input, out.String(), "lines should pass through unchanged")
+			assert.Equal(t, tt.want, tl.GetValue())
+		})
+	}
+}
+
+func TestCountFileLines(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		content string
+		want    int
+	}{
+		{content: "", want: 0},
+		{content: "a\nb\nc\n", want: 3},
+		{content: "a\nb\nc", want: 3},
+	}
+
+	for i, tt := range tests {
+		path := filepath.Join(dir, strings.Repeat("f", i+1))
+		assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+		got, err := countFileLines(path)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, "content %q", tt.content)
+	}
+
+	_, err := countFileLines(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestPipeOptions_CopyTotalFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expected.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("1\n2\n3\n4\n"), 0o600))
+
+	var out, status bytes.Buffer
+	o := pipeOptions{totalFrom: path, discard: true, numeric: true}
+	assert.NoError(t, o.copy(strings.NewReader("a\nb\n"), &out, &status))
+
+	assert.Empty(t, out.String(), "discard should not pass lines through")
+	assert.Equal(t, "50\n", status.String())
+}
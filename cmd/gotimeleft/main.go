@@ -0,0 +1,36 @@
+// Command gotimeleft copies stdin to stdout while showing a progress bar
+// with the estimated time left on stderr, like pv.
+package main
+
+import "os"
+
+const usage = `Usage: gotimeleft [flags] < input > output
+       gotimeleft run [flags] -- command [args...]
+       gotimeleft ffmpeg [flags] < progress
+       gotimeleft pid [flags] PID
+       gotimeleft watch [flags] FILE
+
+Copies stdin to stdout while showing the progress on stderr.
+
+Flags:`
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run dispatches the arguments to the matching mode and returns the exit code
+func run(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "run":
+			return runCommand(args[1:])
+		case "ffmpeg":
+			return runFFmpeg(args[1:])
+		case "pid":
+			return runPID(args[1:])
+		case "watch":
+			return runWatch(args[1:])
+		}
+	}
+	return runPipe(args)
+}
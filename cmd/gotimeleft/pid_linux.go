@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/jonathanhecl/gotimeleft"
+)
+
+const pidUsage = `Usage: gotimeleft pid [flags] PID
+
+Shows the progress of every regular file opened by the process, from the
+position of its descriptor against the file size, until the process exits.
+
+Flags:`
+
+// runPID shows the progress of the files opened by another process
+func runPID(args []string) int {
+	fs := flag.NewFlagSet("gotimeleft pid", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), pidUsage)
+		fs.PrintDefaults()
+	}
+
+	interval := fs.Duration("i", time.Second, "update interval")
+	width := fs.Int("w", 30, "progress bar width")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	pid, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gotimeleft: invalid pid", fs.Arg(0))
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	lines := 0
+	r := newRenderer(io.Discard, nil, *interval, *width, formatSize)
+	w := &gotimeleft.ProcessWatcher{
+		PID:      pid,
+		Interval: *interval,
+		OnUpdate: func(files []gotimeleft.ProcessFile) {
+			lines = renderProcessFiles(os.Stderr, r, files, lines)
+		},
+	}
+	if err := w.Run(ctx); err != nil && err != context.Canceled {
+		fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+		return 1
+	}
+	return 0
+}
+
+// renderProcessFiles redraws one progress line per file over the previously drawn lines
+// and returns the number of lines drawn
+func renderProcessFiles(w io.Writer, r *renderer, files []gotimeleft.ProcessFile, previous int) int {
+	if previous > 0 {
+		fmt.Fprintf(w, "\033[%dA", previous)
+	}
+	for _, f := range files {
+		fmt.Fprintf(w, "\033[K%s %s\n", f.Path, r.line(f.TimeLeft.Snapshot(), false))
+	}
+	for i := len(files); i < previous; i++ {
+		fmt.Fprint(w, "\033[K\n")
+	}
+	if len(files) < previous {
+		return previous
+	}
+	return len(files)
+}
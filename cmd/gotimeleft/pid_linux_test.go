@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonathanhecl/gotimeleft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderProcessFiles(t *testing.T) {
+	r := newRenderer(io.Discard, nil, time.Hour, 10, formatSize)
+	files := []gotimeleft.ProcessFile{
+		{FD: 3, Path: "/data/a.tar", TimeLeft: gotimeleft.Init(2048).Value(1024)},
+		{FD: 4, Path: "/data/b.tar", TimeLeft: gotimeleft.Init(1024)},
+	}
+
+	var out bytes.Buffer
+	assert.Equal(t, 2, renderProcessFiles(&out, r, files, 0))
+	assert.Contains(t, out.String(), "/data/a.tar [====>.....] 50.0% 1.0KiB/2.0KiB")
+	assert.Equal(t, 2, strings.Count(out.String(), "\n"))
+
+	// Closed files leave blank lines behind instead of stale ones
+	out.Reset()
+	assert.Equal(t, 2, renderProcessFiles(&out, r, files[1:], 2))
+	assert.True(t, strings.HasPrefix(out.String(), "\033[2A"))
+	assert.NotContains(t, out.String(), "a.tar")
+}
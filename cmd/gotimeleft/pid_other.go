@@ -0,0 +1,14 @@
+//go:build !linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runPID is only supported on Linux, which exposes descriptor positions in /proc
+func runPID(args []string) int {
+	fmt.Fprintln(os.Stderr, "gotimeleft: pid is only supported on Linux")
+	return 1
+}
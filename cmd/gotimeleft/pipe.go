@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jonathanhecl/gotimeleft"
+)
+
+type (
+	// pipeOptions are the options shared by the modes that copy stdin to stdout
+	pipeOptions struct {
+		size      string
+		rate      string
+		lines     bool
+		totalFrom string
+		discard   bool
+		quiet     bool
+		numeric   bool
+		interval  time.Duration
+		width     int
+	}
+)
+
+// register adds the options to a flag set
+func (o *pipeOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.size, "s", "", "expected size in bytes, with optional binary unit suffix (K, M, G, T), or in lines with -l, with optional decimal unit suffix (k, M, G, T)")
+	fs.StringVar(&o.rate, "L", "", "limit the transfer to this many bytes per second, with optional unit suffix")
+	fs.BoolVar(&o.lines, "l", false, "line mode: count lines instead of bytes")
+	fs.StringVar(&o.totalFrom, "total-from", "", "line mode with the expected number of lines read from this file's line count")
+	fs.BoolVar(&o.discard, "discard", false, "do not pass the input through to stdout")
+	fs.BoolVar(&o.quiet, "q", false, "quiet: do not show any progress")
+	fs.BoolVar(&o.numeric, "n", false, "numeric: print the integer percentage (or count without -s) on each update")
+	fs.DurationVar(&o.interval, "i", time.Second, "update interval")
+	fs.IntVar(&o.width, "w", 30, "progress bar width")
+}
+
+// runPipe copies stdin to stdout while rendering the progress on stderr
+func runPipe(args []string) int {
+	fs := flag.NewFlagSet("gotimeleft", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	var o pipeOptions
+	o.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := o.copy(os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+		return 1
+	}
+	return 0
+}
+
+// copy copies in to out, rendering the progress on status
+func (o *pipeOptions) copy(in io.Reader, out, status io.Writer) error {
+	var total int64
+	if o.size != "" {
+		// Lines are counted with decimal suffixes, as they are displayed
+		parse := parseSize
+		if o.lines || o.totalFrom != "" {
+			parse = parseCount
+		}
+		var err error
+		if total, err = parse(o.size); err != nil {
+			return err
+		}
+	}
+	if o.totalFrom != "" {
+		o.lines = true
+		lines, err := countFileLines(o.totalFrom)
+		if err != nil {
+			return err
+		}
+		total = int64(lines)
+	}
+	if o.discard {
+		out = io.Discard
+	}
+
+	if o.rate != "" {
+		rate, err := parseSize(o.rate)
+		if err != nil {
+			return err
+		}
+		if rate > 0 {
+			in = newRateLimitedReader(in, rate)
+		}
+	}
+
+	t := gotimeleft.Init(int(total))
+	if o.lines {
+		r := o.renderer(status, t, formatCount)
+		defer r.finish()
+
+		return copyLines(out, in, t)
+	}
+
+	r := o.renderer(status, t, formatSize)
+	defer r.finish()
+
+	_, err := io.Copy(out, gotimeleft.NewReader(in, t))
+	return err
+}
+
+// renderer returns a started renderer, or a silent one in quiet mode
+func (o *pipeOptions) renderer(status io.Writer, t *gotimeleft.TimeLeft, format func(n float64) string) *renderer {
+	if o.quiet {
+		status = io.Discard
+	}
+
+	r := newRenderer(status, t, o.interval, o.width, format)
+	r.numeric = o.numeric
+	r.start()
+	return r
+}
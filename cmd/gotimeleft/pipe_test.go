@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeOptions_Copy(t *testing.T) {
+	input := strings.Repeat("line\n", 1000)
+
+	tests := []struct {
+		name       string
+		options    pipeOptions
+		wantStatus []string
+	}{
+		{
+			name:       "Bytes with size",
+			options:    pipeOptions{size: "5000", width: 10},
+			wantStatus: []string{"[==========] 100.0% 4.9KiB/4.9KiB", "\n"},
+		},
+		{
+			name:       "Bytes without size",
+			options:    pipeOptions{width: 10},
+			wantStatus: []string{"4.9KiB "},
+		},
+		{
+			name:       "Lines",
+			options:    pipeOptions{size: "1000", lines: true, width: 10},
+			wantStatus: []string{"100.0% 1.0k/1.0k"},
+		},
+		{
+			name:       "Lines with decimal suffix",
+			options:    pipeOptions{size: "1k", lines: true, width: 10},
+			wantStatus: []string{"100.0% 1.0k/1.0k"},
+		},
+		{
+			name:       "Numeric",
+			options:    pipeOptions{size: "5000", numeric: true},
+			wantStatus: []string{"100\n"},
+		},
+		{
+			name:    "Quiet",
+			options: pipeOptions{size: "5000", quiet: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, status bytes.Buffer
+			assert.NoError(t, tt.options.copy(strings.NewReader(input), &out, &status))
+			assert.Equal(t, input, out.String())
+
+			if len(tt.wantStatus) == 0 {
+				assert.Empty(t, status.String())
+			}
+			for _, want := range tt.wantStatus {
+				assert.Contains(t, status.String(), want)
+			}
+		})
+	}
+}
+
+func TestPipeOptions_CopyRateLimit(t *testing.T) {
+	var out, status bytes.Buffer
+	o := pipeOptions{rate: "10K", quiet: true}
+
+	start := time.Now()
+	assert.NoError(t, o.copy(strings.NewReader(strings.Repeat("x", 2048)), &out, &status))
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	assert.Equal(t, 2048, out.Len())
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"io"
+	"time"
+)
+
+type (
+	// rateLimitedReader is an io.Reader that reads at most rate bytes per second
+	rateLimitedReader struct {
+		r     io.Reader
+		rate  float64
+		start time.Time
+		read  int64
+	}
+)
+
+// newRateLimitedReader wraps r so it reads at most rate bytes per second
+func newRateLimitedReader(r io.Reader, rate int64) *rateLimitedReader {
+	return &rateLimitedReader{r: r, rate: float64(rate)}
+}
+
+// Read reads in small chunks and sleeps to keep the average below the rate
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if r.start.IsZero() {
+		r.start = time.Now()
+	}
+
+	// Read a tenth of a second worth of data at a time to keep the flow smooth
+	chunk := int(r.rate / 10)
+	if chunk < 1 {
+		chunk = 1
+	}
+	if len(p) > chunk {
+		p = p[:chunk]
+	}
+
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+
+	expected := time.Duration(float64(r.read) / r.rate * float64(time.Second))
+	if wait := expected - time.Since(r.start); wait > 0 {
+		time.Sleep(wait)
+	}
+	return n, err
+}
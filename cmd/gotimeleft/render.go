@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonathanhecl/gotimeleft"
+)
+
+type (
+	// rawWriter writes to w above the progress line of r
+	rawWriter struct {
+		r *renderer
+		w io.Writer
+	}
+
+	// renderer periodically writes the progress of a TimeLeft to a terminal
+	renderer struct {
+		w        io.Writer
+		t        *gotimeleft.TimeLeft
+		interval time.Duration
+		width    int
+		numeric  bool
+		format   func(n float64) string
+		rate     func(s gotimeleft.Snapshot) string
+
+		stop     chan struct{}
+		stopped  chan struct{}
+		stopOnce sync.Once
+		mu       sync.Mutex
+		lastLen  int
+	}
+)
+
+// newRenderer creates a renderer writing to w, formatting values with format
+func newRenderer(w io.Writer, t *gotimeleft.TimeLeft, interval time.Duration, width int, format func(n float64) string) *renderer {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &renderer{
+		w:        w,
+		t:        t,
+		interval: interval,
+		width:    width,
+		format:   format,
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// start renders the progress every interval until finish is called
+func (r *renderer) start() {
+	go func() {
+		defer close(r.stopped)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.render(r.t.Snapshot(), false)
+			}
+		}
+	}()
+}
+
+// finish stops rendering and writes the final state
+func (r *renderer) finish() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		<-r.stopped
+		r.render(r.t.Snapshot(), true)
+	})
+}
+
+// printLine writes a line of output above the progress line
+func (r *renderer) printLine(w io.Writer, line string) {
+	r.mu.Lock()
+	r.clearLine()
+	fmt.Fprintln(w, line)
+	r.mu.Unlock()
+
+	if !r.numeric {
+		r.render(r.t.Snapshot(), false)
+	}
+}
+
+// printRaw writes output as is, clearing the progress line first
+func (r *renderer) printRaw(w io.Writer, data []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clearLine()
+	return w.Write(data)
+}
+
+// clearLine erases the progress line, the lock being held
+func (r *renderer) clearLine() {
+	if r.lastLen > 0 && !r.numeric {
+		fmt.Fprint(r.w, "\r"+strings.Repeat(" ", r.lastLen)+"\r")
+		r.lastLen = 0
+	}
+}
+
+// Write passes p through to the writer
+func (w rawWriter) Write(p []byte) (int, error) {
+	return w.r.printRaw(w.w, p)
+}
+
+// render writes a snapshot, replacing the previous line unless in numeric mode
+func (r *renderer) render(s gotimeleft.Snapshot, final bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.numeric {
+		if s.Total > 0 {
+			fmt.Fprintf(r.w, "%d\n", int(s.Fraction*100))
+		} else {
+			fmt.Fprintf(r.w, "%d\n", s.Value)
+		}
+		return
+	}
+
+	line := r.line(s, final)
+	padding := ""
+	if len(line) < r.lastLen {
+		padding = strings.Repeat(" ", r.lastLen-len(line))
+	}
+	r.lastLen = len(line)
+
+	end := ""
+	if final {
+		end = "\n"
+	}
+	fmt.Fprint(r.w, "\r"+line+padding+end)
+}
+
+// line formats a snapshot as a status line
+func (r *renderer) line(s gotimeleft.Snapshot, final bool) string {
+	parts := make([]string, 0, 6)
+	if s.Total > 0 {
+		parts = append(parts,
+			s.ProgressBar(r.width),
+			s.Progress(1),
+			r.format(float64(s.Value))+"/"+r.format(float64(s.Total)),
+		)
+	} else {
+		parts = append(parts, r.format(float64(s.Value)))
+	}
+	if r.rate != nil {
+		parts = append(parts, r.rate(s))
+	} else {
+		parts = append(parts, r.format(s.PerSecond)+"/s")
+	}
+
+	switch {
+	case final || s.Status == gotimeleft.StatusDone:
+		parts = append(parts, "in "+s.Elapsed.Round(time.Second).String())
+	case s.Total > 0 && s.Estimated:
+		parts = append(parts, "ETA "+s.TimeLeft.Round(time.Second).String())
+	case s.Total > 0:
+		parts = append(parts, "ETA --")
+	default:
+		parts = append(parts, s.Elapsed.Round(time.Second).String())
+	}
+	return strings.Join(parts, " ")
+}
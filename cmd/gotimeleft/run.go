@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jonathanhecl/gotimeleft"
+)
+
+const runUsage = `Usage: gotimeleft run [flags] -- command [args...]
+
+Runs the command and shows a progress bar parsed from its output. The pattern
+must capture either the named groups "value" and "total" (or "value" with -s),
+or the named group "percent". Lines that do not match are passed through.
+
+Flags:`
+
+// defaultPattern matches "45%", "45.5%" and "10/200"
+const defaultPattern = `(?P<percent>\d+(?:\.\d+)?)\s*%|(?P<value>\d+)\s*/\s*(?P<total>\d+)`
+
+// percentScale is the total used for percentages, to keep two decimals
+const percentScale = 10000
+
+type (
+	// progressMatcher extracts progress values from lines of output
+	progressMatcher struct {
+		re      *regexp.Regexp
+		value   int
+		total   int
+		percent int
+		size    int
+	}
+)
+
+// newProgressMatcher compiles the pattern, size is the total used when the pattern has no total group
+func newProgressMatcher(pattern string, size int) (*progressMatcher, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &progressMatcher{
+		re:      re,
+		value:   re.SubexpIndex("value"),
+		total:   re.SubexpIndex("total"),
+		percent: re.SubexpIndex("percent"),
+		size:    size,
+	}
+	if m.percent < 0 && m.value < 0 {
+		return nil, errors.New(`pattern must capture "value" or "percent"`)
+	}
+	if m.percent < 0 && m.total < 0 && size <= 0 {
+		return nil, errors.New(`pattern without a "total" group needs -s`)
+	}
+	return m, nil
+}
+
+// match returns the value and total of the last progress found in the line
+func (m *progressMatcher) match(line string) (value, total int, ok bool) {
+	matches := m.re.FindAllStringSubmatch(line, -1)
+	if len(matches) == 0 {
+		return 0, 0, false
+	}
+	groups := matches[len(matches)-1]
+
+	if m.percent >= 0 && groups[m.percent] != "" {
+		percent, err := strconv.ParseFloat(groups[m.percent], 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		return int(percent * percentScale / 100), percentScale, true
+	}
+
+	if m.value < 0 || groups[m.value] == "" {
+		return 0, 0, false
+	}
+	value, err := strconv.Atoi(groups[m.value])
+	if err != nil {
+		return 0, 0, false
+	}
+	total = m.size
+	if m.total >= 0 && groups[m.total] != "" {
+		if total, err = strconv.Atoi(groups[m.total]); err != nil {
+			return 0, 0, false
+		}
+	}
+	return value, total, total > 0
+}
+
+// runCommand runs a child command, rendering the progress parsed from its output
+func runCommand(args []string) int {
+	fs := flag.NewFlagSet("gotimeleft run", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), runUsage)
+		fs.PrintDefaults()
+	}
+
+	pattern := fs.String("pattern", defaultPattern, "regular expression capturing value and total, or percent")
+	size := fs.Int("s", 0, "total when the pattern only captures value")
+	quiet := fs.Bool("q", false, "quiet: do not show any progress")
+	interval := fs.Duration("i", time.Second, "update interval")
+	width := fs.Int("w", 30, "progress bar width")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	m, err := newProgressMatcher(*pattern, *size)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+		return 2
+	}
+
+	status := io.Writer(os.Stderr)
+	if *quiet {
+		status = io.Discard
+	}
+
+	t := gotimeleft.Init(*size)
+	r := newRenderer(status, t, *interval, *width, formatCount)
+	r.start()
+
+	code := runChild(fs.Args(), m, t, r)
+	r.finish()
+	return code
+}
+
+// runChild runs the command, feeding its output to t, and returns its exit code
+func runChild(args []string, m *progressMatcher, t *gotimeleft.TimeLeft, r *renderer) int {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+		return 1
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+		return 1
+	}
+
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+		return 127
+	}
+
+	// Forward the signals to the child, which decides how to exit
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+	go func() {
+		for sig := range signals {
+			cmd.Process.Signal(sig)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		scanProgress(stdout, os.Stdout, m, t, r)
+	}()
+	go func() {
+		defer wg.Done()
+		scanProgress(stderr, os.Stderr, m, t, r)
+	}()
+	wg.Wait()
+
+	return exitCode(cmd.Wait())
+}
+
+// maxLineLength is the longest line scanned for progress, longer ones end the scan
+const maxLineLength = 1024 * 1024
+
+// scanProgress feeds the matching lines of in to t and passes the others through to out. After
+// an over-long line or a read error, the rest of in is passed through as is, so the child never
+// blocks on a full pipe.
+func scanProgress(in io.Reader, out io.Writer, m *progressMatcher, t *gotimeleft.TimeLeft, r *renderer) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	scanner.Split(scanTerminalLines)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) >= maxLineLength {
+			r.printRaw(out, scanner.Bytes())
+			break
+		}
+		line := scanner.Text()
+		if value, total, ok := m.match(line); ok {
+			t.SetTotal(total).Value(value)
+			continue
+		}
+		r.printLine(out, line)
+	}
+	io.Copy(rawWriter{r: r, w: out}, in)
+}
+
+// scanTerminalLines is a bufio.SplitFunc splitting on "\n" and on the "\r" progress updates use,
+// "\r\n" ending a single line
+func scanTerminalLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\r' {
+			if i+1 < len(data) && data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			if i+1 == len(data) && !atEOF && len(data) < maxLineLength {
+				// Wait for the next byte, which may be the "\n" of a "\r\n"
+				return 0, nil, nil
+			}
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	if len(data) >= maxLineLength {
+		// The buffer is full without a line ending: stop instead of failing with bufio.ErrTooLong
+		return len(data), data, bufio.ErrFinalToken
+	}
+	return 0, nil, nil
+}
+
+// exitCode returns the exit code of a finished command, 128+signal when it was killed
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+		return 1
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return exitErr.ExitCode()
+}
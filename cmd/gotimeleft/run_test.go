@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonathanhecl/gotimeleft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressMatcher(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		pattern   string
+		size      int
+		line      string
+		wantValue int
+		wantTotal int
+		wantOk    bool
+	}{
+		{
+			name:      "Fraction",
+			line:      "Receiving objects: 120/480",
+			wantValue: 120,
+			wantTotal: 480,
+			wantOk:    true,
+		},
+		{
+			name:      "Percent",
+			line:      "  1,234,567  45.5%  1.23MB/s  0:00:12",
+			wantValue: 4550,
+			wantTotal: percentScale,
+			wantOk:    true,
+		},
+		{
+			name:      "Last match wins",
+			line:      "10% 20% 30%",
+			wantValue: 3000,
+			wantTotal: percentScale,
+			wantOk:    true,
+		},
+		{
+			name:   "No match",
+			line:   "Connecting to example.com",
+			wantOk: false,
+		},
+		{
+			name:      "Custom pattern with size",
+			pattern:   `processed (?P<value>\d+) records`,
+			size:      1000,
+			line:      "processed 250 records",
+			wantValue: 250,
+			wantTotal: 1000,
+			wantOk:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := tt.pattern
+			if pattern == "" {
+				pattern = defaultPattern
+			}
+			m, err := newProgressMatcher(pattern, tt.size)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			value, total, ok := m.match(tt.line)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantValue, value)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
+func TestNewProgressMatcher_Errors(t *testing.T) {
+	_, err := newProgressMatcher(`(`, 0)
+	assert.Error(t, err)
+
+	_, err = newProgressMatcher(`(\d+)`, 0)
+	assert.Error(t, err, "pattern without named groups")
+
+	_, err = newProgressMatcher(`(?P<value>\d+)`, 0)
+	assert.Error(t, err, "pattern without total needs a size")
+}
+
+func TestScanProgress(t *testing.T) {
+	m, _ := newProgressMatcher(defaultPattern, 0)
+	tl := gotimeleft.Init(0)
+	r := newRenderer(io.Discard, tl, time.Hour, 30, formatCount)
+
+	var out bytes.Buffer
+	scanProgress(strings.NewReader("starting\n1/4\r2/4\r3/4\ndone\n"), &out, m, tl, r)
+
+	assert.Equal(t, "starting\ndone\n", out.String(), "non-matching lines should pass through")
+	assert.Equal(t, 3, tl.GetValue())
+	assert.Equal(t, 4, tl.Snapshot().Total)
+}
+
+func TestScanProgress_BlankLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Blank lines", input: "a\n\nb\n\n", want: "a\n\nb\n\n"},
+		{name: "CRLF", input: "a\r\n\r\nb\r\n", want: "a\n\nb\n"},
+		{name: "Progress before a blank line", input: "1/4\r2/4\n\ndone\n", want: "\ndone\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newProgressMatcher(defaultPattern, 0)
+			tl := gotimeleft.Init(0)
+			r := newRenderer(io.Discard, tl, time.Hour, 30, formatCount)
+
+			var out bytes.Buffer
+			scanProgress(strings.NewReader(tt.input), &out, m, tl, r)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestScanProgress_LongLine(t *testing.T) {
+	m, _ := newProgressMatcher(defaultPattern, 0)
+	tl := gotimeleft.Init(0)
+	r := newRenderer(io.Discard, tl, time.Hour, 30, formatCount)
+
+	// A line longer than the scanner buffer, written through a pipe like a child's output
+	input := "1/4\n" + strings.Repeat("x", 2*maxLineLength) + "\nafter\n2/4\n"
+	pr, pw := io.Pipe()
+	go func() {
+		io.WriteString(pw, input)
+		pw.Close()
+	}()
+
+	var out bytes.Buffer
+	scanProgress(pr, &out, m, tl, r)
+
+	assert.Equal(t, input[len("1/4\n"):], out.String(), "the rest should pass through as is")
+	assert.Equal(t, 1, tl.GetValue())
+}
+
+func TestScanTerminalLines_SplitCRLF(t *testing.T) {
+	// A "\r" at the end of the buffer waits for the next byte
+	advance, token, err := scanTerminalLines([]byte("a\r"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, advance)
+	assert.Nil(t, token)
+
+	advance, token, _ = scanTerminalLines([]byte("a\r"), true)
+	assert.Equal(t, 2, advance)
+	assert.Equal(t, []byte("a"), token)
+}
+
+func TestRunChild_ExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("needs a POSIX shell")
+	}
+
+	tests := []struct {
+		name      string
+		script    string
+		wantCode  int
+		wantValue int
+	}{
+		{name: "Success", script: "echo 1/4; echo 4/4", wantCode: 0, wantValue: 4},
+		{name: "Failure", script: "echo 2/4 >&2; exit 3", wantCode: 3, wantValue: 2},
+		{name: "Killed", script: "echo 1/4; kill -TERM $$", wantCode: 128 + 15, wantValue: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newProgressMatcher(defaultPattern, 0)
+			tl := gotimeleft.Init(0)
+			r := newRenderer(io.Discard, tl, time.Hour, 30, formatCount)
+
+			assert.Equal(t, tt.wantCode, runChild([]string{"sh", "-c", tt.script}, m, tl, r))
+			assert.Equal(t, tt.wantValue, tl.GetValue())
+		})
+	}
+
+	assert.Equal(t, 127, runChild([]string{"/nonexistent/command"}, nil, gotimeleft.Init(0), nil))
+}
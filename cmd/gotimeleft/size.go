@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits are the binary unit suffixes, each 1024 times the previous one
+var sizeUnits = []string{"", "K", "M", "G", "T", "P"}
+
+// parseSize parses a size with an optional binary unit suffix (1024, 1.5K, 10M, 2GiB, 1TB)
+func parseSize(s string) (int64, error) {
+	value := strings.TrimSpace(s)
+	upper := strings.ToUpper(value)
+	upper = strings.TrimSuffix(upper, "B")
+	upper = strings.TrimSuffix(upper, "I")
+
+	multiplier := 1.0
+	for i := len(sizeUnits) - 1; i > 0; i-- {
+		if strings.HasSuffix(upper, sizeUnits[i]) {
+			upper = strings.TrimSuffix(upper, sizeUnits[i])
+			for j := 0; j < i; j++ {
+				multiplier *= 1024
+			}
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * multiplier), nil
+}
+
+// countUnits are the decimal unit suffixes of counts, each 1000 times the previous one, as
+// formatted by formatCount
+var countUnits = []string{"", "K", "M", "G", "T"}
+
+// parseCount parses a number of items with an optional decimal unit suffix (1000, 1.5k, 10M)
+func parseCount(s string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+
+	multiplier := 1.0
+	for i := len(countUnits) - 1; i > 0; i-- {
+		if strings.HasSuffix(upper, countUnits[i]) {
+			upper = strings.TrimSuffix(upper, countUnits[i])
+			for j := 0; j < i; j++ {
+				multiplier *= 1000
+			}
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid count %q", s)
+	}
+	return int64(n * multiplier), nil
+}
+
+// formatSize formats a number of bytes with a binary unit suffix (1.5MiB)
+func formatSize(n float64) string {
+	unit := 0
+	for n >= 1024 && unit < len(sizeUnits)-1 {
+		n /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return strconv.FormatFloat(n, 'f', 0, 64) + "B"
+	}
+	return strconv.FormatFloat(n, 'f', 1, 64) + sizeUnits[unit] + "iB"
+}
+
+// formatCount formats a number of items with a decimal unit suffix (1.5k)
+func formatCount(n float64) string {
+	suffixes := []string{"", "k", "M", "G", "T"}
+	unit := 0
+	for n >= 1000 && unit < len(suffixes)-1 {
+		n /= 1000
+		unit++
+	}
+	if unit == 0 {
+		return strconv.FormatFloat(n, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(n, 'f', 1, 64) + suffixes[unit]
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "1024", want: 1024},
+		{input: "1K", want: 1024},
+		{input: "1.5k", want: 1536},
+		{input: "10M", want: 10 * 1024 * 1024},
+		{input: "2GiB", want: 2 * 1024 * 1024 * 1024},
+		{input: "1TB", want: 1024 * 1024 * 1024 * 1024},
+		{input: " 512B ", want: 512},
+		{input: "", wantErr: true},
+		{input: "ten", wantErr: true},
+		{input: "-1K", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseSize(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseCount(t *testing.T) {
+
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "1000", want: 1000},
+		{input: "5k", want: 5000},
+		{input: "1.5K", want: 1500},
+		{input: "10M", want: 10 * 1000 * 1000},
+		{input: " 2G ", want: 2 * 1000 * 1000 * 1000},
+		{input: "", wantErr: true},
+		{input: "1KiB", wantErr: true},
+		{input: "-1k", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseCount(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512B", formatSize(512))
+	assert.Equal(t, "1.5KiB", formatSize(1536))
+	assert.Equal(t, "10.0MiB", formatSize(10*1024*1024))
+	assert.Equal(t, "999", formatCount(999))
+	assert.Equal(t, "1.5k", formatCount(1500))
+}
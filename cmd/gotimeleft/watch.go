@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jonathanhecl/gotimeleft"
+)
+
+const watchUsage = `Usage: gotimeleft watch [flags] FILE
+
+Watches a file written by another program and shows its progress against the
+expected size, until the size is reached or the sentinel file appears. The file
+may not exist yet; when it is truncated or replaced the progress starts over.
+
+Flags:`
+
+// runWatch shows the progress of a growing file
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("gotimeleft watch", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), watchUsage)
+		fs.PrintDefaults()
+	}
+
+	size := fs.String("s", "", "expected final size, with optional unit suffix (K, M, G, T)")
+	sentinel := fs.String("sentinel", "", "file whose appearance marks completion")
+	quiet := fs.Bool("q", false, "quiet: do not show any progress")
+	numeric := fs.Bool("n", false, "numeric: print the integer percentage (or size without -s) on each update")
+	interval := fs.Duration("i", time.Second, "update interval")
+	width := fs.Int("w", 30, "progress bar width")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 || (*size == "" && *sentinel == "") {
+		fs.Usage()
+		return 2
+	}
+
+	var target int64
+	if *size != "" {
+		var err error
+		if target, err = parseSize(*size); err != nil {
+			fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+			return 2
+		}
+	}
+
+	status := io.Writer(os.Stderr)
+	if *quiet {
+		status = io.Discard
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	t := gotimeleft.Init(int(target))
+	r := newRenderer(status, t, *interval, *width, formatSize)
+	r.numeric = *numeric
+	w := &gotimeleft.FileWatcher{
+		Path:     fs.Arg(0),
+		Target:   target,
+		Sentinel: *sentinel,
+		Interval: *interval,
+		OnUpdate: func(t *gotimeleft.TimeLeft) {
+			r.render(t.Snapshot(), false)
+		},
+	}
+
+	err := w.Run(ctx, t)
+	r.render(t.Snapshot(), true)
+	if err != nil && err != context.Canceled {
+		fmt.Fprintln(os.Stderr, "gotimeleft:", err)
+		return 1
+	}
+	return 0
+}
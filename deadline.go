@@ -0,0 +1,124 @@
+package gotimeleft
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// DeadlineOverrunError is the cause reported when the projected completion misses the deadline
+	DeadlineOverrunError struct {
+		Deadline  time.Time
+		Projected time.Time
+		Overrun   time.Duration
+	}
+
+	// DeadlineGuard cancels a derived context once the projected overrun has been stable for a grace period
+	DeadlineGuard struct {
+		ctx    context.Context
+		cancel context.CancelFunc
+
+		mu    sync.Mutex
+		cause error
+	}
+)
+
+func (e *DeadlineOverrunError) Error() string {
+	return fmt.Sprintf("gotimeleft: projected completion at %s overruns deadline %s by %s",
+		e.Projected.Format(time.RFC3339), e.Deadline.Format(time.RFC3339), e.Overrun.Round(time.Millisecond))
+}
+
+// CheckDeadline reports whether the time left plus the safety margin fits before the context deadline.
+// The error is a *DeadlineOverrunError when it does not fit. It returns nil when the context has
+// no deadline or there is no estimate yet.
+func CheckDeadline(ctx context.Context, t *TimeLeft, margin time.Duration) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	t.mu.Lock()
+	timeLeft, ok := t.timeLeft()
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	projected := time.Now().Add(timeLeft + margin)
+	if !projected.After(deadline) {
+		return nil
+	}
+	return &DeadlineOverrunError{
+		Deadline:  deadline,
+		Projected: projected,
+		Overrun:   projected.Sub(deadline),
+	}
+}
+
+// GuardDeadline derives a context from parent that is cancelled once CheckDeadline has reported
+// an overrun continuously for the grace period. The overrun is available from Cause.
+func GuardDeadline(parent context.Context, t *TimeLeft, margin, grace time.Duration) *DeadlineGuard {
+	ctx, cancel := context.WithCancel(parent)
+	g := &DeadlineGuard{ctx: ctx, cancel: cancel}
+
+	interval := grace / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	} else if interval > time.Second {
+		interval = time.Second
+	}
+
+	go g.watch(t, margin, grace, interval)
+	return g
+}
+
+// Context returns the guarded context
+func (g *DeadlineGuard) Context() context.Context {
+	return g.ctx
+}
+
+// Cause returns the overrun that cancelled the context, or nil
+func (g *DeadlineGuard) Cause() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.cause
+}
+
+// Stop cancels the guarded context and stops checking the deadline
+func (g *DeadlineGuard) Stop() {
+	g.cancel()
+}
+
+func (g *DeadlineGuard) watch(t *TimeLeft, margin, grace, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var overrunSince time.Time
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case <-t.Done():
+			return
+		case now := <-ticker.C:
+			overrun := CheckDeadline(g.ctx, t, margin)
+			if overrun == nil {
+				overrunSince = time.Time{}
+				continue
+			}
+			if overrunSince.IsZero() {
+				overrunSince = now
+			}
+			if now.Sub(overrunSince) >= grace {
+				g.mu.Lock()
+				g.cause = overrun
+				g.mu.Unlock()
+				g.cancel()
+				return
+			}
+		}
+	}
+}
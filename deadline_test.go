@@ -0,0 +1,117 @@
+package gotimeleft
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTimeLeft returns a tracker with a steady speed history
+func newTestTimeLeft(total, value int, speedPerMicrosecond float64) *TimeLeft {
+	tl := Init(total)
+	tl.lastValue = value
+	tl.speedPerMicrosecond = speedPerMicrosecond
+	for i := 0; i < tl.maxHistorySize; i++ {
+		tl.speedHistory = append(tl.speedHistory, speedPerMicrosecond)
+	}
+	return tl
+}
+
+func TestCheckDeadline(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		timeLeft    *TimeLeft
+		deadline    time.Duration
+		margin      time.Duration
+		wantOverrun bool
+	}{
+		{
+			name:        "No deadline",
+			timeLeft:    newTestTimeLeft(100, 0, 0.000001), // 100s left
+			wantOverrun: false,
+		},
+		{
+			name:        "No estimate yet",
+			timeLeft:    Init(100),
+			deadline:    time.Second,
+			wantOverrun: false,
+		},
+		{
+			name:        "Fits before deadline",
+			timeLeft:    newTestTimeLeft(100, 50, 0.00001), // 5s left
+			deadline:    time.Minute,
+			wantOverrun: false,
+		},
+		{
+			name:        "Margin overruns deadline",
+			timeLeft:    newTestTimeLeft(100, 50, 0.00001), // 5s left
+			deadline:    time.Minute,
+			margin:      time.Minute,
+			wantOverrun: true,
+		},
+		{
+			name:        "Overruns deadline",
+			timeLeft:    newTestTimeLeft(100, 0, 0.000001), // 100s left
+			deadline:    time.Second,
+			wantOverrun: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			// Assigned to an error, nil must stay nil
+			var err error = CheckDeadline(ctx, tt.timeLeft, tt.margin)
+			if !tt.wantOverrun {
+				assert.NoError(t, err)
+				assert.True(t, err == nil, "no overrun should be a nil error")
+				return
+			}
+			var overrun *DeadlineOverrunError
+			if assert.True(t, errors.As(err, &overrun)) {
+				assert.Greater(t, overrun.Overrun, time.Duration(0))
+				assert.Contains(t, err.Error(), "overruns deadline")
+			}
+		})
+	}
+}
+
+func TestGuardDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	guard := GuardDeadline(ctx, newTestTimeLeft(100, 0, 0.000001), 0, 20*time.Millisecond)
+	defer guard.Stop()
+
+	select {
+	case <-guard.Context().Done():
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("guarded context should be cancelled on a stable overrun")
+	}
+
+	var overrun *DeadlineOverrunError
+	assert.True(t, errors.As(guard.Cause(), &overrun))
+	assert.NoError(t, ctx.Err(), "parent context should not be cancelled")
+}
+
+func TestGuardDeadline_Feasible(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	guard := GuardDeadline(ctx, newTestTimeLeft(100, 50, 0.00001), 0, 20*time.Millisecond)
+	defer guard.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(t, guard.Context().Err())
+	assert.Nil(t, guard.Cause())
+}
@@ -0,0 +1,56 @@
+package gotimeleft
+
+import "context"
+
+// Done returns a channel that is closed when the value reaches the total or Finish is called.
+// Reset does not close it: the waiters of an unfinished run wait for the new run to be done.
+func (t *TimeLeft) Done() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done == nil {
+		t.done = make(chan struct{})
+		if t.finished {
+			close(t.done)
+		}
+	}
+	return t.done
+}
+
+// Wait blocks until the task is done or the context is cancelled
+func (t *TimeLeft) Wait(ctx context.Context) error {
+	select {
+	case <-t.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Finish marks the task as done without reaching the total
+func (t *TimeLeft) Finish() *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.finish()
+	return t
+}
+
+// IsDone returns true if the task is done
+func (t *TimeLeft) IsDone() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.finished
+}
+
+// finish closes the done channel exactly once
+func (t *TimeLeft) finish() {
+	if t.finished {
+		return
+	}
+	t.finished = true
+	if t.done != nil {
+		close(t.done)
+	}
+}
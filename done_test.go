@@ -0,0 +1,136 @@
+package gotimeleft
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeLeft_Done(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		setupFunc func(tl *TimeLeft)
+		want      bool
+	}{
+		{
+			name:      "Not started",
+			setupFunc: func(tl *TimeLeft) {},
+			want:      false,
+		},
+		{
+			name: "Partial progress",
+			setupFunc: func(tl *TimeLeft) {
+				tl.Value(50)
+				tl.Value(80)
+			},
+			want: false,
+		},
+		{
+			name: "Value reaches total",
+			setupFunc: func(tl *TimeLeft) {
+				tl.Value(50)
+				tl.Value(100)
+			},
+			want: true,
+		},
+		{
+			name: "Steps exceed total",
+			setupFunc: func(tl *TimeLeft) {
+				tl.Step(60)
+				tl.Step(60)
+			},
+			want: true,
+		},
+		{
+			name: "Finished explicitly",
+			setupFunc: func(tl *TimeLeft) {
+				tl.Step(10)
+				tl.Finish()
+				tl.Finish()
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := Init(100)
+			done := tl.Done()
+			tt.setupFunc(tl)
+
+			select {
+			case <-done:
+				assert.True(t, tt.want, "done channel should be open")
+			default:
+				assert.False(t, tt.want, "done channel should be closed")
+			}
+			assert.Equal(t, tt.want, tl.IsDone())
+		})
+	}
+}
+
+func TestTimeLeft_DoneAfterCompletion(t *testing.T) {
+	tl := Init(10).Step(10)
+
+	select {
+	case <-tl.Done():
+	default:
+		t.Fatal("done channel requested after completion should be closed")
+	}
+
+	tl.Reset(10)
+	select {
+	case <-tl.Done():
+		t.Fatal("reset should start a new run")
+	default:
+	}
+}
+
+func TestTimeLeft_Wait(t *testing.T) {
+	tl := Init(10)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		tl.Step(10)
+	}()
+	assert.NoError(t, tl.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, Init(10).Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestTimeLeft_ResetKeepsWaiters(t *testing.T) {
+	tl := Init(100)
+	tl.Step(50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	waited := make(chan error, 1)
+	go func() { waited <- tl.Wait(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+	tl.Reset(100)
+	select {
+	case err := <-waited:
+		t.Fatalf("a reset should not complete the waiters, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	assert.False(t, tl.IsDone(), "reset should start a new run")
+
+	tl.Step(100)
+	assert.NoError(t, <-waited, "the waiters should be released when the new run is done")
+}
+
+func TestTimeLeft_FinishedTimeLeft(t *testing.T) {
+	tl := newTestTimeLeft(100, 50, 0.00001).Finish()
+
+	assert.Equal(t, time.Duration(0), tl.GetTimeLeft())
+
+	// Unfinished, the 50 values left at 10/s would overrun the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, CheckDeadline(ctx, tl, 0))
+}
@@ -0,0 +1,256 @@
+package gotimeleft
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// EnsembleMode selects how an EnsembleEstimator combines its models
+	EnsembleMode int
+
+	// EnsembleEstimator is an Estimator running several estimators on the same progress,
+	// scoring each by how well its past estimates matched the progress observed afterwards
+	EnsembleEstimator struct {
+		mu      sync.Mutex
+		mode    EnsembleMode
+		horizon time.Duration
+		models  []*ensembleModel
+	}
+
+	// ModelScore is the score of a model of an EnsembleEstimator
+	ModelScore struct {
+		Name string
+		// Error is the moving average of |ln(predicted/actual)| of the time taken to make the
+		// observed progress, 0 being perfect, NaN before the first scored prediction
+		Error float64
+		// Scored is the number of predictions checked against the observed progress
+		Scored int
+		// Weight is the share of the model in a blend, or 1 for the model served in EnsembleBest
+		// mode, when every model has an estimate
+		Weight float64
+	}
+
+	ensembleModel struct {
+		name      string
+		estimator Estimator
+		pending   []ensemblePrediction
+		error     float64
+		scored    int
+	}
+
+	// ensemblePrediction is the rate implied by an estimate made at a given progress
+	ensemblePrediction struct {
+		at    time.Time
+		value int
+		rate  float64
+	}
+)
+
+const (
+	// EnsembleBest serves the estimate of the model with the lowest error
+	EnsembleBest EnsembleMode = iota
+	// EnsembleBlend serves the average of the estimates, weighted by the inverse of the errors
+	EnsembleBlend
+)
+
+const (
+	// ensembleMaxPending bounds the predictions waiting to be scored per model
+	ensembleMaxPending = 16
+	// ensembleErrorSmoothing is the weight of the latest scored prediction in the error
+	ensembleErrorSmoothing = 0.2
+	// ensembleUnscoredError is the error assumed for models without scored predictions
+	ensembleUnscoredError = 1.0
+)
+
+// NewEnsembleEstimator creates an ensemble combining its models with the given mode
+func NewEnsembleEstimator(mode EnsembleMode) *EnsembleEstimator {
+	return &EnsembleEstimator{
+		mode:    mode,
+		horizon: 5 * time.Second,
+	}
+}
+
+// Add adds a model. In EnsembleBest mode, models added first are preferred until scores are known.
+func (e *EnsembleEstimator) Add(name string, estimator Estimator) *EnsembleEstimator {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.models = append(e.models, &ensembleModel{name: name, estimator: estimator})
+	return e
+}
+
+// SetHorizon sets how long after an estimate it is checked against the observed progress
+// (5 seconds by default). Estimates are recorded at most four times per horizon.
+func (e *EnsembleEstimator) SetHorizon(horizon time.Duration) *EnsembleEstimator {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if horizon > 0 {
+		e.horizon = horizon
+	}
+	return e
+}
+
+// Observe forwards the value to every model and scores their estimates made at least a horizon ago
+func (e *EnsembleEstimator) Observe(at time.Time, value int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, m := range e.models {
+		m.estimator.Observe(at, value)
+		m.score(at, value, e.horizon)
+	}
+}
+
+// score checks the pending predictions old enough against the progress made since, keeping
+// those without progress yet for later
+func (m *ensembleModel) score(at time.Time, value int, horizon time.Duration) {
+	pending := m.pending[:0]
+	for _, p := range m.pending {
+		elapsed := at.Sub(p.at)
+		if elapsed < horizon || value <= p.value {
+			pending = append(pending, p)
+			continue
+		}
+
+		predicted := float64(value-p.value) / p.rate
+		err := math.Abs(math.Log(predicted / elapsed.Seconds()))
+		if m.scored == 0 {
+			m.error = err
+		} else {
+			m.error += ensembleErrorSmoothing * (err - m.error)
+		}
+		m.scored++
+	}
+	m.pending = pending
+}
+
+// Estimate returns the estimate of the best model, or the weighted blend of all of them
+func (e *EnsembleEstimator) Estimate(now time.Time, value, total int) (time.Duration, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	estimates, weights := e.estimate(now, value, total)
+
+	var sum, weightSum float64
+	best := -1
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		if best < 0 || w > weights[best] {
+			best = i
+		}
+		sum += w * float64(estimates[i])
+		weightSum += w
+	}
+	if best < 0 {
+		return 0, false
+	}
+	if e.mode == EnsembleBest {
+		return estimates[best], true
+	}
+	return time.Duration(sum / weightSum), true
+}
+
+// estimate asks every model for an estimate, records it as a prediction to score, and returns
+// the estimates with their weights, 0 for models without an estimate
+func (e *EnsembleEstimator) estimate(now time.Time, value, total int) ([]time.Duration, []float64) {
+	estimates := make([]time.Duration, len(e.models))
+	weights := make([]float64, len(e.models))
+	for i, m := range e.models {
+		estimate, ok := m.estimator.Estimate(now, value, total)
+		if !ok {
+			continue
+		}
+		estimates[i] = estimate
+		weights[i] = 1 / (m.currentError() + 0.01)
+
+		if estimate > 0 && total > value {
+			m.predict(now, value, float64(total-value)/estimate.Seconds(), e.horizon)
+		}
+	}
+	return estimates, weights
+}
+
+// predict records the rate implied by an estimate, at most four times per horizon
+func (m *ensembleModel) predict(now time.Time, value int, rate float64, horizon time.Duration) {
+	if n := len(m.pending); n > 0 && now.Sub(m.pending[n-1].at) < horizon/4 {
+		return
+	}
+	m.pending = append(m.pending, ensemblePrediction{at: now, value: value, rate: rate})
+	if len(m.pending) > ensembleMaxPending {
+		m.pending = m.pending[1:]
+	}
+}
+
+// currentError returns the error of the model, assuming a neutral one before it is scored
+func (m *ensembleModel) currentError() float64 {
+	if m.scored == 0 {
+		return ensembleUnscoredError
+	}
+	return m.error
+}
+
+// Scores returns the score of every model, in the order they were added
+func (e *EnsembleEstimator) Scores() []ModelScore {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	scores := make([]ModelScore, len(e.models))
+	var weightSum float64
+	best := -1
+	for i, m := range e.models {
+		scores[i] = ModelScore{Name: m.name, Error: math.NaN(), Scored: m.scored}
+		if m.scored > 0 {
+			scores[i].Error = m.error
+		}
+		scores[i].Weight = 1 / (m.currentError() + 0.01)
+		weightSum += scores[i].Weight
+		if best < 0 || scores[i].Weight > scores[best].Weight {
+			best = i
+		}
+	}
+
+	for i := range scores {
+		if e.mode == EnsembleBest {
+			scores[i].Weight = 0
+			if i == best {
+				scores[i].Weight = 1
+			}
+		} else {
+			scores[i].Weight /= weightSum
+		}
+	}
+	return scores
+}
+
+// rebase restarts the models within the same run, the models keeping the observations of the
+// run only restarting their speed estimate
+func (e *EnsembleEstimator) rebase() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, m := range e.models {
+		if r, ok := m.estimator.(rebaser); ok {
+			r.rebase()
+		} else {
+			m.estimator.Reset()
+		}
+		m.pending = nil
+	}
+}
+
+// Reset forgets the observations of every model and the estimates waiting to be scored,
+// keeping the scores so the models known to suit the job stay preferred
+func (e *EnsembleEstimator) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, m := range e.models {
+		m.estimator.Reset()
+		m.pending = nil
+	}
+}
@@ -0,0 +1,110 @@
+package gotimeleft
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// scaledEstimator multiplies the estimates of another estimator
+type scaledEstimator struct {
+	Estimator
+	factor float64
+}
+
+func (s scaledEstimator) Estimate(now time.Time, value, total int) (time.Duration, bool) {
+	d, ok := s.Estimator.Estimate(now, value, total)
+	return time.Duration(float64(d) * s.factor), ok
+}
+
+// runEnsemble feeds a steady trace of 100 values per second, asking for an estimate at every point
+func runEnsemble(e *EnsembleEstimator, start time.Time) (time.Time, int) {
+	var now time.Time
+	var value int
+	for _, p := range noisyTrace(1, start, 100, time.Minute, 200*time.Millisecond, 0) {
+		e.Observe(p.at, p.value)
+		e.Estimate(p.at, p.value, 100000)
+		now, value = p.at, p.value
+	}
+	return now, value
+}
+
+func TestEnsembleEstimator(t *testing.T) {
+	tests := []struct {
+		name string
+		mode EnsembleMode
+		// Estimate relative to the accurate one
+		wantFactor float64
+		tolerance  float64
+	}{
+		{name: "Best", mode: EnsembleBest, wantFactor: 1, tolerance: 0.01},
+		{name: "Blend", mode: EnsembleBlend, wantFactor: 1, tolerance: 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The pessimistic model is added first, so it is served until the scores are known
+			e := NewEnsembleEstimator(tt.mode).
+				Add("pessimistic", scaledEstimator{NewRegressionEstimator(30, 0), 3}).
+				Add("accurate", NewRegressionEstimator(30, 0)).
+				Add("optimistic", scaledEstimator{NewRegressionEstimator(30, 0), 0.5})
+
+			now, value := runEnsemble(e, time.Now())
+
+			scores := e.Scores()
+			if assert.Len(t, scores, 3) {
+				assert.Equal(t, "accurate", scores[1].Name)
+				assert.InDelta(t, 0, scores[1].Error, 0.01)
+				assert.InDelta(t, math.Log(3), scores[0].Error, 0.05)
+				assert.InDelta(t, math.Log(2), scores[2].Error, 0.05)
+				assert.Greater(t, scores[1].Scored, 30)
+				assert.Greater(t, scores[1].Weight, 0.9)
+			}
+
+			want := time.Duration(float64(100000-value) / 100 * float64(time.Second))
+			got, ok := e.Estimate(now, value, 100000)
+			assert.True(t, ok)
+			assert.InEpsilon(t, float64(want), float64(got), tt.tolerance)
+		})
+	}
+}
+
+func TestEnsembleEstimator_Unscored(t *testing.T) {
+	now := time.Now()
+	e := NewEnsembleEstimator(EnsembleBest).
+		Add("none", &stubEstimator{}).
+		Add("first", &stubEstimator{estimate: time.Minute}).
+		Add("second", &stubEstimator{estimate: time.Hour})
+
+	got, ok := e.Estimate(now, 0, 100)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, got, "the first model with an estimate is served until scores are known")
+
+	for _, s := range e.Scores() {
+		assert.True(t, math.IsNaN(s.Error))
+		assert.Equal(t, 0, s.Scored)
+	}
+
+	_, ok = NewEnsembleEstimator(EnsembleBlend).Add("none", &stubEstimator{}).Estimate(now, 0, 100)
+	assert.False(t, ok)
+}
+
+func TestEnsembleEstimator_Reset(t *testing.T) {
+	stub := &stubEstimator{estimate: time.Minute}
+	e := NewEnsembleEstimator(EnsembleBest).SetHorizon(time.Second).Add("stub", stub)
+
+	now := time.Now()
+	e.Observe(now, 0)
+	e.Estimate(now, 0, 6000) // 100 values per second
+	e.Observe(now.Add(2*time.Second), 100)
+
+	scores := e.Scores()
+	assert.Equal(t, 1, scores[0].Scored)
+	assert.InDelta(t, math.Log(2), scores[0].Error, 1e-9, "the progress took twice the predicted time")
+
+	e.Reset()
+	assert.Equal(t, 1, stub.resets)
+	assert.Equal(t, 1, e.Scores()[0].Scored, "scores should survive a reset")
+}
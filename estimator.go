@@ -0,0 +1,51 @@
+package gotimeleft
+
+import "time"
+
+type (
+	// Estimator estimates the time left from the values observed over time.
+	// TimeLeft calls it under its lock, so implementations only need their own
+	// locking for methods called from elsewhere.
+	Estimator interface {
+		// Observe records the value reached at the given time
+		Observe(at time.Time, value int)
+		// Estimate returns the time left from now to go from value to total,
+		// or false when there is not enough data yet
+		Estimate(now time.Time, value, total int) (time.Duration, bool)
+		// Reset forgets every observation
+		Reset()
+	}
+
+	// rebaser is implemented by estimators keeping the observations of the run when the speed
+	// changes or tracking resumes mid-run, so only their speed estimate starts over
+	rebaser interface {
+		rebase()
+	}
+)
+
+// SetEstimator replaces the built-in speed averaging used by GetTimeLeft with the estimator.
+// A nil estimator restores the built-in one.
+func (t *TimeLeft) SetEstimator(e Estimator) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.estimator = e
+	t.resetEstimator(true)
+	return t
+}
+
+// resetEstimator restarts the estimator from the current value. Within the same run, estimators
+// keeping the observations of the run only restart their speed estimate.
+func (t *TimeLeft) resetEstimator(newRun bool) {
+	if t.estimator == nil {
+		return
+	}
+	if r, ok := t.estimator.(rebaser); ok && !newRun {
+		r.rebase()
+	} else {
+		t.estimator.Reset()
+	}
+	if !t.lastStepTime.IsZero() {
+		t.estimator.Observe(t.lastStepTime, t.lastValue)
+	}
+}
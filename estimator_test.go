@@ -0,0 +1,87 @@
+package gotimeleft
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// stubEstimator records observations and returns a fixed estimate
+	stubEstimator struct {
+		values   []int
+		resets   int
+		estimate time.Duration
+	}
+
+	// tracePoint is a timestamped value of a synthetic progress trace
+	tracePoint struct {
+		at    time.Time
+		value int
+	}
+)
+
+func (s *stubEstimator) Observe(at time.Time, value int) {
+	s.values = append(s.values, value)
+}
+
+func (s *stubEstimator) Estimate(now time.Time, value, total int) (time.Duration, bool) {
+	return s.estimate, s.estimate > 0
+}
+
+func (s *stubEstimator) Reset() {
+	s.values = nil
+	s.resets++
+}
+
+// noisyTrace returns a trace progressing at rate values per second for the duration, sampled
+// every interval with jittered timing and Gaussian noise of the given standard deviation
+func noisyTrace(seed int64, start time.Time, rate float64, duration, interval time.Duration, noise float64) []tracePoint {
+	rnd := rand.New(rand.NewSource(seed))
+
+	var trace []tracePoint
+	for elapsed := time.Duration(0); elapsed <= duration; {
+		value := rate*elapsed.Seconds() + rnd.NormFloat64()*noise
+		trace = append(trace, tracePoint{at: start.Add(elapsed), value: int(math.Max(0, value))})
+
+		jitter := 0.5 + rnd.Float64()
+		elapsed += time.Duration(float64(interval) * jitter)
+	}
+	return trace
+}
+
+// observeTrace feeds a trace to an estimator and returns the time of the last point
+func observeTrace(e Estimator, trace []tracePoint) time.Time {
+	for _, p := range trace {
+		e.Observe(p.at, p.value)
+	}
+	return trace[len(trace)-1].at
+}
+
+func TestTimeLeft_SetEstimator(t *testing.T) {
+	stub := &stubEstimator{}
+	tl := Init(100)
+
+	assert.Equal(t, 24*time.Hour, tl.SetEstimator(stub).GetTimeLeft(), "no estimate yet")
+	assert.Equal(t, []int{0}, stub.values, "the starting point should be observed")
+
+	tl.Step(10).Value(30)
+	assert.Equal(t, []int{0, 10, 30}, stub.values)
+
+	stub.estimate = time.Minute
+	assert.Equal(t, time.Minute, tl.GetTimeLeft())
+	assert.Equal(t, time.Minute, tl.Snapshot().TimeLeft)
+
+	tl.Reset(100)
+	assert.Equal(t, 2, stub.resets)
+	assert.Equal(t, []int{0}, stub.values)
+
+	tl.Value(100)
+	assert.Equal(t, time.Duration(0), tl.GetTimeLeft(), "completed tasks have no time left")
+
+	tl.SetEstimator(nil).Reset(100)
+	assert.Equal(t, 24*time.Hour, tl.GetTimeLeft(), "built-in estimation should be restored")
+}
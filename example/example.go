@@ -14,8 +14,8 @@ func main() {
 	for i := 0; i < 110; i++ {
 		time.Sleep(100 * time.Microsecond) // Simulate a long process
 
-		timeleft.Step(1)
-		fmt.Printf("%s Time left: %s - %s - %s - Speed: %.2f/s\n", timeleft.GetProgressBar(30), timeleft.GetTimeLeft().String(), timeleft.GetProgressValues(), timeleft.GetProgress(1), timeleft.GetPerSecond())
+		snapshot := timeleft.Step(1).Snapshot()
+		fmt.Printf("%s Time left: %s - %s - %s - Speed: %.2f/s\n", snapshot.ProgressBar(30), snapshot.TimeLeft.String(), snapshot.ProgressValues(), snapshot.Progress(1), snapshot.PerSecond)
 	}
 
 	fmt.Printf("Done! in %s\n", timeleft.GetTimeSpent().String())
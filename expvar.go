@@ -0,0 +1,23 @@
+package gotimeleft
+
+import "expvar"
+
+// Var returns an expvar.Var that renders a live JSON snapshot of the progress,
+// ready for expvar.Publish
+func (t *TimeLeft) Var() expvar.Var {
+	return expvar.Func(func() any {
+		return t.Snapshot()
+	})
+}
+
+// Var returns an expvar.Var that renders a live JSON snapshot of every registered
+// TimeLeft keyed by name, ready for expvar.Publish
+func (r *Registry) Var() expvar.Var {
+	return expvar.Func(func() any {
+		snapshots := make(map[string]Snapshot)
+		for _, s := range r.Snapshots() {
+			snapshots[s.Name] = s.Snapshot
+		}
+		return snapshots
+	})
+}
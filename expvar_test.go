@@ -0,0 +1,39 @@
+package gotimeleft
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeLeft_Var(t *testing.T) {
+	tl := Init(100)
+	v := tl.Var()
+
+	var got map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(v.String()), &got))
+	assert.Equal(t, 0.0, got["value"])
+	assert.Equal(t, "pending", got["status"])
+	assert.Nil(t, got["time_left_seconds"])
+
+	tl.Step(40)
+	assert.NoError(t, json.Unmarshal([]byte(v.String()), &got))
+	assert.Equal(t, 40.0, got["value"])
+	assert.Equal(t, 100.0, got["total"])
+	assert.Equal(t, 0.4, got["fraction"])
+	assert.Equal(t, "running", got["status"])
+	assert.NotNil(t, got["time_left_seconds"])
+}
+
+func TestRegistry_Var(t *testing.T) {
+	r := NewRegistry()
+	r.Register("copy", Init(100).Step(10))
+	r.Register("archive", Init(10).Step(10))
+
+	var got map[string]map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(r.Var().String()), &got))
+	assert.Len(t, got, 2)
+	assert.Equal(t, 10.0, got["copy"]["value"])
+	assert.Equal(t, "done", got["archive"]["status"])
+}
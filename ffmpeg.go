@@ -0,0 +1,83 @@
+package gotimeleft
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// FFmpegProgress is a block of the key=value stream written by ffmpeg -progress
+	FFmpegProgress struct {
+		Frame     int
+		FPS       float64
+		OutTime   time.Duration
+		TotalSize int64
+		Speed     float64 // Multiple of realtime, 0 when unknown
+		Done      bool
+	}
+)
+
+// ReadFFmpegProgress reads the stream written by ffmpeg -progress and calls fn after every block
+func ReadFFmpegProgress(r io.Reader, fn func(p FFmpegProgress)) error {
+	var p FFmpegProgress
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "frame":
+			p.Frame, _ = strconv.Atoi(value)
+		case "fps":
+			p.FPS, _ = strconv.ParseFloat(value, 64)
+		case "out_time_us", "out_time_ms": // Both are in microseconds
+			if us, err := strconv.ParseInt(value, 10, 64); err == nil {
+				p.OutTime = time.Duration(us) * time.Microsecond
+			}
+		case "out_time":
+			if d, err := ParseFFmpegTime(value); err == nil {
+				p.OutTime = d
+			}
+		case "total_size":
+			p.TotalSize, _ = strconv.ParseInt(value, 10, 64)
+		case "speed":
+			p.Speed, _ = strconv.ParseFloat(strings.TrimSuffix(value, "x"), 64)
+		case "progress":
+			p.Done = value == "end"
+			fn(p)
+		}
+	}
+	return scanner.Err()
+}
+
+// ParseFFmpegTime parses ffmpeg timestamps (01:23:45.67, 23:45.6) and plain seconds (83.45)
+func ParseFFmpegTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	parts := strings.Split(strings.TrimPrefix(s, "-"), ":")
+	if len(parts) > 3 || parts[0] == "" {
+		return 0, fmt.Errorf("invalid ffmpeg time %q", s)
+	}
+
+	var seconds float64
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("invalid ffmpeg time %q", s)
+		}
+		seconds = seconds*60 + v
+	}
+
+	d := time.Duration(seconds * float64(time.Second))
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,81 @@
+package gotimeleft
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFFmpegTime(t *testing.T) {
+
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "00:01:23.45", want: 83*time.Second + 450*time.Millisecond},
+		{input: "01:00:00.000000", want: time.Hour},
+		{input: "23:45.5", want: 23*time.Minute + 45500*time.Millisecond},
+		{input: "83.45", want: 83*time.Second + 450*time.Millisecond},
+		{input: "-00:00:01.5", want: -1500 * time.Millisecond},
+		{input: "N/A", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "1:2:3:4", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFFmpegTime(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, float64(tt.want), float64(got), float64(time.Microsecond))
+		})
+	}
+}
+
+func TestReadFFmpegProgress(t *testing.T) {
+	stream := `frame=120
+fps=30.00
+stream_0_0_q=28.0
+bitrate= 512.3kbits/s
+total_size=262144
+out_time_us=4000000
+out_time_ms=4000000
+out_time=00:00:04.000000
+dup_frames=0
+drop_frames=0
+speed=2.01x
+progress=continue
+frame=240
+fps=30.00
+total_size=524288
+out_time=00:00:08.500000
+speed=N/A
+progress=end
+`
+
+	var got []FFmpegProgress
+	assert.NoError(t, ReadFFmpegProgress(strings.NewReader(stream), func(p FFmpegProgress) {
+		got = append(got, p)
+	}))
+
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, FFmpegProgress{
+			Frame:     120,
+			FPS:       30,
+			OutTime:   4 * time.Second,
+			TotalSize: 262144,
+			Speed:     2.01,
+		}, got[0])
+
+		assert.Equal(t, 240, got[1].Frame)
+		assert.Equal(t, 8500*time.Millisecond, got[1].OutTime)
+		assert.Equal(t, 0.0, got[1].Speed)
+		assert.True(t, got[1].Done)
+	}
+}
@@ -0,0 +1,76 @@
+package gotimeleft
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+type (
+	// FileWatcher polls the size of a growing file and feeds it into a TimeLeft
+	FileWatcher struct {
+		Path     string
+		Target   int64  // Final size, 0 or less when only the sentinel marks completion
+		Sentinel string // Optional path whose appearance marks completion
+		Interval time.Duration
+		OnUpdate func(t *TimeLeft)
+	}
+)
+
+// Run polls the file every interval until it reaches the target size or the sentinel appears,
+// returning nil, or until the context is cancelled. The file may not exist yet; when it is
+// truncated or replaced (rotated) the progress starts over.
+func (w *FileWatcher) Run(ctx context.Context, t *TimeLeft) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	t.SetTotal(int(w.Target))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last os.FileInfo
+	for {
+		info, err := os.Stat(w.Path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			last = nil
+		case err != nil:
+			return err
+		case last == nil:
+			t.resume(int(info.Size()))
+			last = info
+		case !os.SameFile(last, info) || info.Size() < last.Size():
+			t.Reset(int(w.Target)).resume(int(info.Size()))
+			last = info
+		default:
+			if info.Size() != last.Size() {
+				t.Value(int(info.Size()))
+			}
+			last = info
+		}
+
+		if w.Sentinel != "" {
+			if _, err := os.Stat(w.Sentinel); err == nil {
+				t.Finish()
+			}
+		}
+		if last != nil && w.Target > 0 && last.Size() >= w.Target {
+			t.Finish()
+		}
+		if w.OnUpdate != nil {
+			w.OnUpdate(t)
+		}
+		if t.IsDone() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
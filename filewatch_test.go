@@ -0,0 +1,100 @@
+package gotimeleft
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runFileWatcher runs the watcher in the background, recording the value seen on every update
+func runFileWatcher(w *FileWatcher, t *TimeLeft) (values func() []int, result chan error, cancel context.CancelFunc) {
+	var mu sync.Mutex
+	var seen []int
+	w.Interval = 5 * time.Millisecond
+	w.OnUpdate = func(t *TimeLeft) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, t.GetValue())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	result = make(chan error, 1)
+	go func() { result <- w.Run(ctx, t) }()
+
+	return func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), seen...)
+	}, result, cancel
+}
+
+// lastValueIs waits until the last value seen by the watcher is want
+func lastValueIs(values func() []int, want int) func() bool {
+	return func() bool {
+		seen := values()
+		return len(seen) > 0 && seen[len(seen)-1] == want
+	}
+}
+
+func TestFileWatcher_Target(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "download.part")
+	tl := Init(0)
+	values, result, cancel := runFileWatcher(&FileWatcher{Path: path, Target: 300}, tl)
+	defer cancel()
+
+	// The file does not exist yet
+	assert.Eventually(t, lastValueIs(values, 0), time.Second, time.Millisecond)
+
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 100)), 0o600))
+	assert.Eventually(t, lastValueIs(values, 100), time.Second, time.Millisecond)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	assert.NoError(t, err)
+	f.WriteString(strings.Repeat("x", 200))
+	f.Close()
+
+	assert.NoError(t, <-result)
+	assert.Equal(t, 300, tl.GetValue())
+	assert.True(t, tl.IsDone())
+}
+
+func TestFileWatcher_Truncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.sql")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 200)), 0o600))
+
+	tl := Init(0)
+	values, result, cancel := runFileWatcher(&FileWatcher{Path: path, Target: 1000}, tl)
+	defer cancel()
+	assert.Eventually(t, lastValueIs(values, 200), time.Second, time.Millisecond)
+
+	// Rewritten from scratch
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 50)), 0o600))
+	assert.Eventually(t, lastValueIs(values, 50), time.Second, time.Millisecond)
+	assert.Equal(t, 1000, tl.Snapshot().Total)
+
+	cancel()
+	assert.ErrorIs(t, <-result, context.Canceled)
+	assert.False(t, tl.IsDone())
+}
+
+func TestFileWatcher_Sentinel(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backup.tar")
+	sentinel := filepath.Join(dir, "backup.done")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 100)), 0o600))
+
+	tl := Init(0)
+	values, result, cancel := runFileWatcher(&FileWatcher{Path: path, Sentinel: sentinel}, tl)
+	defer cancel()
+	assert.Eventually(t, lastValueIs(values, 100), time.Second, time.Millisecond)
+
+	assert.NoError(t, os.WriteFile(sentinel, nil, 0o600))
+	assert.NoError(t, <-result)
+	assert.True(t, tl.IsDone())
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,7 +16,33 @@ type (
 		lastValue           int
 		lastStepTime        time.Time
 		speedHistory        []float64
+		speedTimes          []time.Time
 		maxHistorySize      int
+
+		mu               sync.Mutex
+		lastProgressTime time.Time // When the value last changed, for stall detection
+		stepGaps         []time.Duration
+		stallTimeout     time.Duration
+		stallGapFactor   float64
+		stalled          bool
+		onStall          func(stalledFor time.Duration)
+		onRecover        func(stalledFor time.Duration)
+
+		done     chan struct{}
+		finished bool
+
+		subscribers map[chan struct{}]struct{}
+
+		estimator   Estimator
+		speedFilter SpeedFilter
+		halfLife    time.Duration
+
+		expectedSpeed      float64
+		expectedConfidence time.Duration
+
+		changeDetector *pageHinkley
+		onSpeedChange  func(from, to float64)
+		speedChangedAt time.Time
 	}
 )
 
@@ -27,6 +54,7 @@ func Init(newTotal int) *TimeLeft {
 		speedPerMicrosecond: 0,
 		lastValue:           0,
 		lastStepTime:        time.Now(),
+		lastProgressTime:    time.Now(),
 		speedHistory:        make([]float64, 0, 30), // Mantener historial de las últimas 30 mediciones
 		maxHistorySize:      30,
 	}
@@ -34,32 +62,100 @@ func Init(newTotal int) *TimeLeft {
 
 // Reset resets the progress
 func (t *TimeLeft) Reset(newTotal int) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.initializationTime = time.Now()
 	t.totalValues = newTotal
 	t.speedPerMicrosecond = 0
 	t.lastValue = 0
 	t.lastStepTime = time.Now()
+	t.lastProgressTime = t.lastStepTime
 	t.speedHistory = make([]float64, 0, t.maxHistorySize)
+	t.speedTimes = nil
+	t.stepGaps = nil
+	t.stalled = false
+	// An unfinished run carries its done channel, and its waiters, into the new run
+	if t.finished {
+		t.done = nil
+		t.finished = false
+	}
+	t.speedChangedAt = time.Time{}
+	if t.changeDetector != nil {
+		t.changeDetector.reset()
+	}
+	t.resetEstimator(true)
 
 	return t
 }
 
 // Step updates the progress with a new step
 func (t *TimeLeft) Step(newStep int) *TimeLeft {
+	return t.update(func() { t.step(newStep) })
+}
+
+// Value updates the progress with a new value
+func (t *TimeLeft) Value(newValue int) *TimeLeft {
+	return t.update(func() { t.value(newValue) })
+}
+
+// update applies a progress change under the lock and fires the callbacks it triggers
+func (t *TimeLeft) update(apply func()) *TimeLeft {
+	t.mu.Lock()
+	previousStepTime, previousValue := t.lastStepTime, t.lastValue
+	apply()
+
+	onSpeedChange := t.detectSpeedChange(previousValue, previousStepTime)
+
+	// Reporting the same value again is not progress, so a poller does not hide a stall
+	var onRecover func(time.Duration)
+	var stalledFor time.Duration
+	if t.lastValue != previousValue {
+		if !t.lastProgressTime.IsZero() {
+			gap := t.lastStepTime.Sub(t.lastProgressTime)
+			t.recordGap(gap)
+			if t.stalled {
+				t.stalled = false
+				onRecover, stalledFor = t.onRecover, gap
+			}
+		}
+		t.lastProgressTime = t.lastStepTime
+	}
+	if t.estimator != nil {
+		t.estimator.Observe(t.lastStepTime, t.lastValue)
+	}
+	if t.totalValues > 0 && t.lastValue >= t.totalValues {
+		t.finish()
+	}
+	t.notifySubscribers()
+	t.mu.Unlock()
+
+	if onRecover != nil {
+		onRecover(stalledFor)
+	}
+	if onSpeedChange != nil {
+		onSpeedChange()
+	}
+	return t
+}
+
+func (t *TimeLeft) step(newStep int) {
 	if t.lastStepTime.IsZero() {
 		t.lastStepTime = time.Now()
 		t.lastValue = newStep
-		return t
+		return
 	}
 
 	change := newStep
 
-	if change > t.totalValues {
+	// A total of 0 or less means the total is unknown
+	if t.totalValues > 0 && t.lastValue+change > t.totalValues {
 		change = t.totalValues - t.lastValue
 		newStep = change
 	}
 
-	elapsedTime := float64(time.Since(t.lastStepTime).Microseconds())
+	now := time.Now()
+	elapsedTime := float64(now.Sub(t.lastStepTime).Microseconds())
 	// Ensure minimum 1μs to prevent division by zero
 	if elapsedTime < 1 {
 		elapsedTime = 1
@@ -71,28 +167,32 @@ func (t *TimeLeft) Step(newStep int) *TimeLeft {
 	} else {
 		t.speedPerMicrosecond = (t.speedPerMicrosecond + speedPerMicrosecond) / 2
 	}
+	sample := t.speedPerMicrosecond
+	if t.halfLife > 0 {
+		// Weighted by age, the speed of each step is averaged rather than the running speed
+		sample = speedPerMicrosecond
+	}
+	t.recordSpeed(sample, now)
 	t.lastValue = t.lastValue + newStep
-	t.lastStepTime = time.Now()
-
-	return t
+	t.lastStepTime = now
 }
 
-// Value updates the progress with a new value
-func (t *TimeLeft) Value(newValue int) *TimeLeft {
+func (t *TimeLeft) value(newValue int) {
 	if t.lastStepTime.IsZero() {
 		t.lastStepTime = time.Now()
 		t.lastValue = newValue
-		return t
+		return
 	}
 
 	change := newValue - t.lastValue
 
-	if change+newValue > t.totalValues {
+	if t.totalValues > 0 && newValue > t.totalValues {
 		change = t.totalValues - t.lastValue
 		newValue = t.totalValues
 	}
 
-	elapsedTime := float64(time.Since(t.lastStepTime).Microseconds())
+	now := time.Now()
+	elapsedTime := float64(now.Sub(t.lastStepTime).Microseconds())
 	// Ensure minimum 1μs to prevent division by zero
 	if elapsedTime < 1 {
 		elapsedTime = 1
@@ -104,29 +204,102 @@ func (t *TimeLeft) Value(newValue int) *TimeLeft {
 	} else {
 		t.speedPerMicrosecond = (t.speedPerMicrosecond + speedPerMicrosecond) / 2
 	}
+	sample := t.speedPerMicrosecond
+	if t.halfLife > 0 {
+		// Weighted by age, the speed of each step is averaged rather than the running speed
+		sample = speedPerMicrosecond
+	}
+	t.recordSpeed(sample, now)
 
 	t.lastValue = newValue
+	t.lastStepTime = now
+}
+
+// resume sets the value reached before tracking started, which says nothing about the speed
+func (t *TimeLeft) resume(value int) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.totalValues > 0 && value > t.totalValues {
+		value = t.totalValues
+	}
+	t.lastValue = value
 	t.lastStepTime = time.Now()
+	t.lastProgressTime = t.lastStepTime
+	if t.changeDetector != nil {
+		t.changeDetector.reset()
+	}
+	t.resetEstimator(false)
+	return t
+}
+
+// SetTotal changes the total without resetting the progress
+func (t *TimeLeft) SetTotal(newTotal int) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
+	t.totalValues = newTotal
+	if t.totalValues > 0 && t.lastValue >= t.totalValues {
+		t.finish()
+	}
 	return t
 }
 
 // GetValue returns the current value
 func (t *TimeLeft) GetValue() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	return t.lastValue
 }
 
 // GetProgressValues returns the progress as a string (10/100)
 func (t *TimeLeft) GetProgressValues() string {
-	return strconv.Itoa(t.lastValue) + "/" + strconv.Itoa(t.totalValues)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return formatProgressValues(t.lastValue, t.totalValues)
 }
 
 // GetProgressBar returns a string representation of the progress bar
 func (t *TimeLeft) GetProgressBar(fullBar int) string {
+	return formatProgressBar(t.GetFloat64(), fullBar)
+}
+
+// GetProgress returns the progress as a string (10.1% 15.5%)
+func (t *TimeLeft) GetProgress(prec int) string { // 10.1% 15.5%
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return formatProgress(t.lastValue, t.totalValues, prec)
+}
+
+// GetFloat64 returns the progress as a float64 (0.0 to 1.0)
+func (t *TimeLeft) GetFloat64() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return progressFraction(t.lastValue, t.totalValues)
+}
+
+// progressFraction returns the progress as a fraction, 0 when the total is unknown
+func progressFraction(value, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(value) / float64(total)
+}
+
+// formatProgressValues returns the progress as a string (10/100)
+func formatProgressValues(value, total int) string {
+	return strconv.Itoa(value) + "/" + strconv.Itoa(total)
+}
+
+// formatProgressBar returns a string representation of the progress bar
+func formatProgressBar(percent float64, fullBar int) string {
 	if fullBar < 1 {
 		fullBar = 30
 	}
-	percent := t.GetFloat64()
 	bar := int(percent * float64(fullBar))
 
 	if bar == 0 {
@@ -138,25 +311,52 @@ func (t *TimeLeft) GetProgressBar(fullBar int) string {
 	}
 }
 
-// GetProgress returns the progress as a string (10.1% 15.5%)
-func (t *TimeLeft) GetProgress(prec int) string { // 10.1% 15.5%
-	return strconv.FormatFloat(float64(t.lastValue)/float64(t.totalValues)*100, 'f', prec, 64) + "%"
+// formatProgress returns the progress as a string (10.1% 15.5%)
+func formatProgress(value, total, prec int) string {
+	return formatPercent(progressFraction(value, total), prec)
 }
 
-// GetFloat64 returns the progress as a float64 (0.0 to 1.0)
-func (t *TimeLeft) GetFloat64() float64 {
-	return float64(t.lastValue) / float64(t.totalValues)
+// formatPercent returns a fraction as a percentage string (10.1%)
+func formatPercent(fraction float64, prec int) string {
+	return strconv.FormatFloat(fraction*100, 'f', prec, 64) + "%"
 }
 
-// calculateAverageSpeed calculates the average speed considering the history
-func (t *TimeLeft) calculateAverageSpeed(newSpeed float64) float64 {
-	// Add the new speed to the history
-	t.speedHistory = append(t.speedHistory, newSpeed)
-	
+// recordSpeed adds the speed measured at a step to the history. With a half-life, speeds are
+// kept while they weigh, for halfLifeHistory half-lives, otherwise the last maxHistorySize are.
+func (t *TimeLeft) recordSpeed(speed float64, at time.Time) {
+	t.speedHistory = append(t.speedHistory, speed)
+	t.speedTimes = append(t.speedTimes, at)
+
+	if t.halfLife > 0 && len(t.speedTimes) == len(t.speedHistory) {
+		oldest := at.Add(-halfLifeHistory * t.halfLife)
+		drop := 0
+		if n := len(t.speedTimes); n > maxTimedHistorySize {
+			drop = n - maxTimedHistorySize
+		}
+		for drop < len(t.speedTimes)-1 && t.speedTimes[drop].Before(oldest) {
+			drop++
+		}
+		if drop > 0 {
+			t.speedHistory = append(t.speedHistory[:0], t.speedHistory[drop:]...)
+			t.speedTimes = append(t.speedTimes[:0], t.speedTimes[drop:]...)
+		}
+		return
+	}
+
 	// Maintain the maximum history size
 	if len(t.speedHistory) > t.maxHistorySize {
 		t.speedHistory = t.speedHistory[1:]
 	}
+	if len(t.speedTimes) > t.maxHistorySize {
+		t.speedTimes = t.speedTimes[1:]
+	}
+}
+
+// calculateAverageSpeed calculates the average speed considering the history, without changing it
+func (t *TimeLeft) calculateAverageSpeed() float64 {
+	if len(t.speedHistory) == 0 {
+		return t.speedPerMicrosecond
+	}
 
 	// If there's not enough data, use a simple average
 	if len(t.speedHistory) < 3 {
@@ -167,29 +367,23 @@ func (t *TimeLeft) calculateAverageSpeed(newSpeed float64) float64 {
 		return sum / float64(len(t.speedHistory))
 	}
 
-	// Calculate mean and standard deviation
-	var sum, sumSq float64
-	for _, s := range t.speedHistory {
-		sum += s
-		sumSq += s * s
-	}
-	mean := sum / float64(len(t.speedHistory))
-	stdDev := math.Sqrt(sumSq/float64(len(t.speedHistory)) - mean*mean)
-
-	// Filter outliers (outside 1.5 standard deviations)
+	// Filter outliers, keeping the time of each speed when it is known
+	lower, upper, center := t.filterBounds(t.speedHistory)
+	timed := t.halfLife > 0 && len(t.speedTimes) == len(t.speedHistory)
 	var filtered []float64
-	lowerBound := mean - 1.5*stdDev
-	upperBound := mean + 1.5*stdDev
-
-	for _, s := range t.speedHistory {
-		if s >= lowerBound && s <= upperBound {
+	var times []time.Time
+	for i, s := range t.speedHistory {
+		if s >= lower && s <= upper {
 			filtered = append(filtered, s)
+			if timed {
+				times = append(times, t.speedTimes[i])
+			}
 		}
 	}
 
-	// If too few values remain after filtering, use the simple mean
+	// If too few values remain after filtering, use the central value
 	if len(filtered) < 3 {
-		return mean
+		return center
 	}
 
 	// Calculate weighted moving average (more weight to recent values)
@@ -197,6 +391,10 @@ func (t *TimeLeft) calculateAverageSpeed(newSpeed float64) float64 {
 	for i, s := range filtered {
 		// Exponential weight: more recent = higher weight
 		weight := math.Exp(float64(i) / float64(len(filtered)))
+		if timed {
+			// Half the weight for every half-life of age, whatever the number of samples in between
+			weight = math.Exp2(-t.speedTimes[len(t.speedTimes)-1].Sub(times[i]).Seconds() / t.halfLife.Seconds())
+		}
 		weightedSum += s * weight
 		weightSum += weight
 	}
@@ -206,25 +404,64 @@ func (t *TimeLeft) calculateAverageSpeed(newSpeed float64) float64 {
 
 // GetTimeLeft returns the time left to complete the task
 func (t *TimeLeft) GetTimeLeft() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if timeLeft, ok := t.timeLeft(); ok {
+		return timeLeft
+	}
+	// If speed is zero or negative, return a large duration instead of infinity
+	return 24 * time.Hour // Default to 24 hours when unable to calculate
+}
+
+// timeLeft estimates the time left, returning false when there is no usable speed yet
+func (t *TimeLeft) timeLeft() (time.Duration, bool) {
+	if t.finished {
+		return 0, true
+	}
+	if t.totalValues <= 0 {
+		// Without a total there is nothing to estimate
+		return 0, false
+	}
+	if t.estimator != nil {
+		if t.totalValues > 0 && t.lastValue >= t.totalValues {
+			return 0, true
+		}
+		if timeLeft, ok := t.estimator.Estimate(time.Now(), t.lastValue, t.totalValues); ok {
+			return timeLeft, true
+		}
+		return t.priorTimeLeft()
+	}
+
 	if t.speedPerMicrosecond <= 0 {
-		// If speed is zero or negative, return a large duration instead of infinity
-		return 24 * time.Hour // Default to 24 hours when unable to calculate
+		return t.priorTimeLeft()
 	}
 
-	estimatedSpeed := t.calculateAverageSpeed(t.speedPerMicrosecond)
+	estimatedSpeed := t.blendPrior(t.calculateAverageSpeed())
 	if estimatedSpeed <= 0 {
-		return 24 * time.Hour
+		return 0, false
 	}
 
-	return time.Duration(float64(t.totalValues-t.lastValue)/estimatedSpeed) * time.Microsecond
+	return time.Duration(float64(t.totalValues-t.lastValue)/estimatedSpeed) * time.Microsecond, true
 }
 
 // GetTimeSpent returns the time elapsed since initialization
 func (t *TimeLeft) GetTimeSpent() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	return time.Since(t.initializationTime)
 }
 
 // GetPerSecond returns the current speed in values per second
 func (t *TimeLeft) GetPerSecond() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	return t.speedPerMicrosecond * 1000
 }
+
+// perSecond returns the current speed in values per second, as used by Snapshot and RequiredRate
+func (t *TimeLeft) perSecond() float64 {
+	return t.speedPerMicrosecond * float64(time.Second/time.Microsecond)
+}
@@ -112,6 +112,17 @@ func TestTimeLeft_GetFloat64(t *testing.T) {
 				assert.Equal(t, expected, got)
 			},
 		},
+		{
+			name: "totalValues 0, lastValue 50",
+			fields: fields{
+				Total:     0,
+				LastValue: 50,
+			},
+			want: 0,
+			checker: func(expected, got float64) {
+				assert.Equal(t, expected, got, "an unknown total should be no progress")
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -193,6 +204,20 @@ func TestTimeLeft_GetProgress(t *testing.T) {
 				assert.Equal(t, expected, got)
 			},
 		},
+		{
+			name: "totalValues 0, lastValue 50",
+			args: args{
+				precision: 1,
+			},
+			fields: fields{
+				Total:     0,
+				LastValue: 50,
+			},
+			want: "0.0%",
+			checker: func(expected, got string) {
+				assert.Equal(t, expected, got)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -274,6 +299,20 @@ func TestTimeLeft_GetProgressBar(t *testing.T) {
 				assert.Equal(t, expected, got)
 			},
 		},
+		{
+			name: "totalValues 0, lastValue 50",
+			args: args{
+				fullBar: 30,
+			},
+			fields: fields{
+				Total:     0,
+				LastValue: 50,
+			},
+			want: "[..............................]",
+			checker: func(expected, got string) {
+				assert.Equal(t, expected, got)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -419,6 +458,18 @@ func TestTimeLeft_GetTimeLeft(t *testing.T) {
 					"Expected time left to be close to %v, got %v", expectedValue, got)
 			},
 		},
+		{
+			name: "Unknown total",
+			fields: fields{
+				Total:               0,
+				LastValue:           50,
+				speedPerMicrosecond: 0.002,
+			},
+			want: 24 * time.Hour,
+			checker: func(expected, got time.Duration) {
+				assert.Equal(t, expected, got, "an unknown total should give no estimate")
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -731,6 +782,50 @@ func TestTimeLeft_Step(t *testing.T) {
 				assert.Greater(t, got.speedPerMicrosecond, float64(0), "speedPerMicrosecond should be greater than 0")
 			},
 		},
+		{
+			name: "Overshooting step",
+			baseFields: fields{
+				Total:               100,
+				SpeedPerMicrosecond: 0.002,
+				LastValue:           90,
+				InitializationTime:  sameTime.Add(-1 * time.Hour),
+				LastStepTime:        sameTime.Add(-1 * time.Second),
+			},
+			args: args{
+				newStep: 20,
+			},
+			want: &TimeLeft{
+				totalValues: 100,
+				lastValue:   100,
+			},
+			checker: func(expected, got *TimeLeft) {
+				assert.Equal(t, expected.totalValues, got.totalValues)
+				assert.Equal(t, expected.lastValue, got.lastValue, "steps past the total should stop at the total")
+				assert.Greater(t, got.speedPerMicrosecond, float64(0), "speedPerMicrosecond should be greater than 0")
+			},
+		},
+		{
+			name: "Unknown total",
+			baseFields: fields{
+				Total:               0,
+				SpeedPerMicrosecond: 0.002,
+				LastValue:           2,
+				InitializationTime:  sameTime.Add(-1 * time.Hour),
+				LastStepTime:        sameTime.Add(-1 * time.Second),
+			},
+			args: args{
+				newStep: 50,
+			},
+			want: &TimeLeft{
+				totalValues: 0,
+				lastValue:   52,
+			},
+			checker: func(expected, got *TimeLeft) {
+				assert.Equal(t, expected.totalValues, got.totalValues)
+				assert.Equal(t, expected.lastValue, got.lastValue, "a total of 0 should not clamp")
+				assert.Greater(t, got.speedPerMicrosecond, float64(0), "speedPerMicrosecond should be greater than 0")
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -816,6 +911,28 @@ func TestTimeLeft_Value(t *testing.T) {
 				assert.Greater(t, got.speedPerMicrosecond, float64(0), "speedPerMicrosecond should be greater than 0")
 			},
 		},
+		{
+			name: "Value past half the total",
+			baseFields: fields{
+				Total:               100,
+				SpeedPerMicrosecond: 0.002,
+				LastValue:           2,
+				InitializationTime:  sameTime.Add(-1 * time.Hour),
+				LastStepTime:        sameTime.Add(-1 * time.Second),
+			},
+			args: args{
+				newValue: 60,
+			},
+			want: &TimeLeft{
+				totalValues: 100,
+				lastValue:   60,
+			},
+			checker: func(expected, got *TimeLeft) {
+				assert.Equal(t, expected.totalValues, got.totalValues)
+				assert.Equal(t, expected.lastValue, got.lastValue, "value should not be clamped to the total")
+				assert.Greater(t, got.speedPerMicrosecond, float64(0), "speedPerMicrosecond should be greater than 0")
+			},
+		},
 		{
 			name: "Exceeding value",
 			baseFields: fields{
@@ -912,3 +1029,39 @@ func TestTimeLeft_GetPerSecond(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeLeft_SetTotal(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		value    int
+		newTotal int
+		wantDone bool
+	}{
+		{
+			name:     "Larger total",
+			value:    50,
+			newTotal: 200,
+			wantDone: false,
+		},
+		{
+			name:     "Total reached",
+			value:    50,
+			newTotal: 50,
+			wantDone: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := Init(100).Value(tt.value)
+			speed := tl.speedPerMicrosecond
+
+			got := tl.SetTotal(tt.newTotal)
+			assert.Equal(t, tt.newTotal, got.totalValues)
+			assert.Equal(t, tt.value, got.lastValue)
+			assert.Equal(t, speed, got.speedPerMicrosecond, "speed should be kept")
+			assert.Equal(t, tt.wantDone, got.IsDone())
+		})
+	}
+}
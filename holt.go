@@ -0,0 +1,151 @@
+package gotimeleft
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// HoltEstimator is an Estimator smoothing the rate and its trend with Holt's linear method,
+	// for jobs that speed up or slow down as they progress
+	HoltEstimator struct {
+		mu sync.Mutex
+
+		alpha     float64
+		beta      float64
+		rateFloor float64
+
+		lastTime  time.Time
+		lastValue int
+		hasRate   bool
+		// Smoothed rate in values per second and its change per second
+		level float64
+		trend float64
+	}
+)
+
+// NewHoltEstimator creates a Holt estimator. alpha smooths the rate and beta its trend, as the
+// weight given to each second of new observations, between 0 and 1, higher values reacting
+// faster; they default to 0.3 and 0.1 when out of range.
+func NewHoltEstimator(alpha, beta float64) *HoltEstimator {
+	if alpha <= 0 || alpha > 1 {
+		alpha = 0.3
+	}
+	if beta <= 0 || beta > 1 {
+		beta = 0.1
+	}
+	return &HoltEstimator{
+		alpha:     alpha,
+		beta:      beta,
+		rateFloor: 0.1,
+	}
+}
+
+// SetRateFloor sets the fraction of the current rate below which a slowing trend is not
+// projected (0.1 by default), so a decelerating job still gets a finite estimate
+func (h *HoltEstimator) SetRateFloor(fraction float64) *HoltEstimator {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if fraction > 0 && fraction <= 1 {
+		h.rateFloor = fraction
+	}
+	return h
+}
+
+// Observe updates the level and trend with the rate since the previous observation
+func (h *HoltEstimator) Observe(at time.Time, value int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.lastTime.IsZero() {
+		h.lastTime, h.lastValue = at, value
+		return
+	}
+
+	dt := at.Sub(h.lastTime).Seconds()
+	if dt <= 0 {
+		return
+	}
+	rate := float64(value-h.lastValue) / dt
+	h.lastTime, h.lastValue = at, value
+
+	if !h.hasRate {
+		h.hasRate = true
+		h.level, h.trend = rate, 0
+		return
+	}
+
+	// Scale the smoothing to the elapsed time, so the sampling frequency does not change the result
+	alpha := 1 - math.Pow(1-h.alpha, dt)
+	beta := 1 - math.Pow(1-h.beta, dt)
+	level := alpha*rate + (1-alpha)*(h.level+h.trend*dt)
+	h.trend = beta*(level-h.level)/dt + (1-beta)*h.trend
+	h.level = level
+}
+
+// Estimate returns the time needed to cover the remaining values at the trending rate
+func (h *HoltEstimator) Estimate(now time.Time, value, total int) (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.hasRate || h.level <= 0 {
+		return 0, false
+	}
+	if value >= total {
+		return 0, true
+	}
+
+	left := h.project(float64(total - value))
+	return time.Duration(left * float64(time.Second)), true
+}
+
+// project returns the seconds needed to cover remaining values, the rate changing linearly
+// with the trend until it reaches the floor
+func (h *HoltEstimator) project(remaining float64) float64 {
+	level, trend := h.level, h.trend
+	if trend == 0 {
+		return remaining / level
+	}
+
+	if trend < 0 {
+		// The rate stops slowing down at the floor, which is reached after floorAt seconds
+		floor := level * h.rateFloor
+		floorAt := (floor - level) / trend
+		covered := level*floorAt + trend*floorAt*floorAt/2
+		if covered < remaining {
+			return floorAt + (remaining-covered)/floor
+		}
+	}
+
+	// Smallest positive root of trend/2*t² + level*t - remaining = 0, in a form that does not
+	// cancel out when the trend is tiny
+	return 2 * remaining / (level + math.Sqrt(level*level+2*trend*remaining))
+}
+
+// Rate returns the smoothed rate in values per second
+func (h *HoltEstimator) Rate() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.level
+}
+
+// Trend returns the smoothed change of the rate, in values per second per second
+func (h *HoltEstimator) Trend() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.trend
+}
+
+// Reset forgets every observation
+func (h *HoltEstimator) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastTime, h.lastValue = time.Time{}, 0
+	h.hasRate = false
+	h.level, h.trend = 0, 0
+}
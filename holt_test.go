@@ -0,0 +1,123 @@
+package gotimeleft
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// trendTrace returns a trace starting at rate values per second and changing by accel
+// values per second every second, sampled every interval for the duration
+func trendTrace(start time.Time, rate, accel float64, duration, interval time.Duration) []tracePoint {
+	var trace []tracePoint
+	for elapsed := time.Duration(0); elapsed <= duration; elapsed += interval {
+		s := elapsed.Seconds()
+		trace = append(trace, tracePoint{at: start.Add(elapsed), value: int(rate*s + accel*s*s/2)})
+	}
+	return trace
+}
+
+func TestHoltEstimator(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name      string
+		rate      float64
+		accel     float64
+		total     int
+		wantRate  float64
+		wantTrend float64
+		// Seconds from the start at which the total is reached
+		finishAt float64
+	}{
+		{
+			name:      "Constant",
+			rate:      50,
+			total:     6000,
+			wantRate:  50,
+			wantTrend: 0,
+			finishAt:  120,
+		},
+		{
+			name:      "Slowing down",
+			rate:      100,
+			accel:     -1,
+			total:     4000,
+			wantRate:  70,
+			wantTrend: -1,
+			finishAt:  100 - math.Sqrt(2000),
+		},
+		{
+			name:      "Speeding up",
+			rate:      10,
+			accel:     2,
+			total:     3000,
+			wantRate:  70,
+			wantTrend: 2,
+			finishAt:  -5 + math.Sqrt(3025),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHoltEstimator(0, 0)
+			trace := trendTrace(start, tt.rate, tt.accel, 30*time.Second, 200*time.Millisecond)
+			now := observeTrace(h, trace)
+
+			assert.InDelta(t, tt.wantRate, h.Rate(), 1)
+			assert.InDelta(t, tt.wantTrend, h.Trend(), 0.1)
+
+			want := time.Duration((tt.finishAt - 30) * float64(time.Second))
+			got, ok := h.Estimate(now, trace[len(trace)-1].value, tt.total)
+			assert.True(t, ok)
+			assert.InEpsilon(t, float64(want), float64(got), 0.05)
+		})
+	}
+}
+
+func TestHoltEstimator_NeverFinishing(t *testing.T) {
+	start := time.Now()
+	h := NewHoltEstimator(0, 0).SetRateFloor(0.2)
+
+	// The rate reaches zero at 5000 values, so 8000 is never reached by the trend alone
+	trace := trendTrace(start, 100, -1, 30*time.Second, 200*time.Millisecond)
+	now := observeTrace(h, trace)
+	value := trace[len(trace)-1].value
+
+	got, ok := h.Estimate(now, value, 8000)
+	assert.True(t, ok)
+	assert.False(t, math.IsInf(got.Seconds(), 0) || math.IsNaN(got.Seconds()))
+
+	// Slows down to 14 values per second after 56 seconds, then stays there
+	floorAt := 56.0
+	covered := 70*floorAt - floorAt*floorAt/2
+	want := floorAt + (float64(8000-value)-covered)/14
+	assert.InEpsilon(t, want, got.Seconds(), 0.05)
+	assert.Greater(t, got.Seconds(), float64(8000-value)/h.Rate(), "slowing down should take longer than the current rate")
+}
+
+func TestHoltEstimator_NotEnoughData(t *testing.T) {
+	h := NewHoltEstimator(0.5, 0.5)
+	now := time.Now()
+
+	h.Observe(now, 0)
+	_, ok := h.Estimate(now, 0, 100)
+	assert.False(t, ok, "one observation gives no rate")
+
+	h.Observe(now.Add(time.Second), 0)
+	_, ok = h.Estimate(now.Add(time.Second), 0, 100)
+	assert.False(t, ok, "no progress gives no estimate")
+
+	h.Reset()
+	h.Observe(now, 0)
+	h.Observe(now.Add(time.Second), 10)
+	got, ok := h.Estimate(now.Add(time.Second), 10, 100)
+	assert.True(t, ok)
+	assert.Equal(t, 9*time.Second, got)
+
+	got, ok = h.Estimate(now.Add(time.Second), 100, 100)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), got)
+}
@@ -0,0 +1,74 @@
+package gotimeleft
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// snapshotJSON is the JSON representation of a Snapshot
+type snapshotJSON struct {
+	Name            string   `json:"name,omitempty"`
+	Value           int      `json:"value"`
+	Total           int      `json:"total"`
+	Fraction        float64  `json:"fraction"`
+	PerSecond       float64  `json:"per_second"`
+	TimeLeftSeconds *float64 `json:"time_left_seconds"`
+	TimeLeft        string   `json:"time_left,omitempty"`
+	ETA             string   `json:"eta,omitempty"`
+	ElapsedSeconds  float64  `json:"elapsed_seconds"`
+	Status          Status   `json:"status"`
+	StartedAt       string   `json:"started_at"`
+	LastStepAt      string   `json:"last_step_at"`
+	TakenAt         string   `json:"taken_at"`
+	SpeedChangedAt  string   `json:"speed_changed_at,omitempty"`
+}
+
+// MarshalJSON encodes the snapshot with durations in seconds and RFC 3339 timestamps.
+// The time left is null and the humanized time left and completion time are omitted
+// when there is no estimate yet.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.toJSON())
+}
+
+func (s Snapshot) toJSON() snapshotJSON {
+	v := snapshotJSON{
+		Value:          s.Value,
+		Total:          s.Total,
+		Fraction:       s.Fraction,
+		PerSecond:      s.PerSecond,
+		ElapsedSeconds: s.Elapsed.Seconds(),
+		Status:         s.Status,
+		StartedAt:      s.StartedAt.Format(time.RFC3339Nano),
+		LastStepAt:     s.LastStepAt.Format(time.RFC3339Nano),
+		TakenAt:        s.TakenAt.Format(time.RFC3339Nano),
+	}
+	if !s.SpeedChangedAt.IsZero() {
+		v.SpeedChangedAt = s.SpeedChangedAt.Format(time.RFC3339Nano)
+	}
+	if s.Estimated {
+		seconds := s.TimeLeft.Seconds()
+		v.TimeLeftSeconds = &seconds
+		v.TimeLeft = humanizeDuration(s.TimeLeft)
+		v.ETA = s.ETA().Format(time.RFC3339)
+	}
+	return v
+}
+
+// MarshalJSON encodes the snapshot like Snapshot.MarshalJSON, with its name
+func (s NamedSnapshot) MarshalJSON() ([]byte, error) {
+	v := s.toJSON()
+	v.Name = s.Name
+	return json.Marshal(v)
+}
+
+// humanizeDuration rounds a duration to a readable precision (1h2m3s, 450ms)
+func humanizeDuration(d time.Duration) string {
+	switch {
+	case d >= time.Minute:
+		return d.Round(time.Second).String()
+	case d >= time.Second:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Millisecond).String()
+	}
+}
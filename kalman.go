@@ -0,0 +1,114 @@
+package gotimeleft
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// KalmanEstimator is an Estimator tracking the value and the rate (values per second)
+	// with a Kalman filter, which smooths noisy and bursty progress without lagging like
+	// a moving average
+	KalmanEstimator struct {
+		mu sync.Mutex
+
+		processNoise     float64
+		measurementNoise float64
+
+		initialized bool
+		lastTime    time.Time
+		value       float64
+		rate        float64
+		// Covariance of the value and rate estimates
+		pVV, pVR, pRR float64
+	}
+)
+
+// kalmanInitialRateVariance expresses that nothing is known about the rate before the second observation
+const kalmanInitialRateVariance = 1e12
+
+// NewKalmanEstimator creates a Kalman filter estimator. processNoise is how much the rate is
+// expected to drift, as the variance of its change per second, and measurementNoise is the
+// variance of the observed values around the true progress. Both default to 1 when not positive.
+func NewKalmanEstimator(processNoise, measurementNoise float64) *KalmanEstimator {
+	if processNoise <= 0 {
+		processNoise = 1
+	}
+	if measurementNoise <= 0 {
+		measurementNoise = 1
+	}
+	return &KalmanEstimator{
+		processNoise:     processNoise,
+		measurementNoise: measurementNoise,
+	}
+}
+
+// Observe updates the filter with the value reached at the given time
+func (k *KalmanEstimator) Observe(at time.Time, value int) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	z := float64(value)
+	if !k.initialized {
+		k.initialized = true
+		k.lastTime = at
+		k.value, k.rate = z, 0
+		k.pVV, k.pVR, k.pRR = k.measurementNoise, 0, kalmanInitialRateVariance
+		return
+	}
+
+	dt := at.Sub(k.lastTime).Seconds()
+	if dt <= 0 {
+		return
+	}
+	k.lastTime = at
+
+	// Predict with a constant rate, the rate drifting as white noise
+	k.value += k.rate * dt
+	q := k.processNoise
+	pVV := k.pVV + 2*dt*k.pVR + dt*dt*k.pRR + q*dt*dt*dt/3
+	pVR := k.pVR + dt*k.pRR + q*dt*dt/2
+	pRR := k.pRR + q*dt
+
+	// Correct with the observed value
+	s := pVV + k.measurementNoise
+	gainV, gainR := pVV/s, pVR/s
+	residual := z - k.value
+	k.value += gainV * residual
+	k.rate += gainR * residual
+	k.pVV = (1 - gainV) * pVV
+	k.pVR = (1 - gainV) * pVR
+	k.pRR = pRR - gainR*pVR
+}
+
+// Estimate returns the time left at the filtered rate
+func (k *KalmanEstimator) Estimate(now time.Time, value, total int) (time.Duration, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.initialized || k.pRR >= kalmanInitialRateVariance || k.rate <= 0 {
+		return 0, false
+	}
+	if value >= total {
+		return 0, true
+	}
+	return time.Duration(float64(total-value) / k.rate * float64(time.Second)), true
+}
+
+// Rate returns the filtered rate in values per second
+func (k *KalmanEstimator) Rate() float64 {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.rate
+}
+
+// Reset forgets every observation
+func (k *KalmanEstimator) Reset() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.initialized = false
+	k.value, k.rate = 0, 0
+	k.pVV, k.pVR, k.pRR = 0, 0, 0
+}
@@ -0,0 +1,104 @@
+package gotimeleft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKalmanEstimator(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name      string
+		trace     []tracePoint
+		total     int
+		wantRate  float64
+		tolerance float64
+	}{
+		{
+			name:      "Steady",
+			trace:     noisyTrace(1, start, 50, time.Minute, 200*time.Millisecond, 0),
+			total:     10000,
+			wantRate:  50,
+			tolerance: 0.01,
+		},
+		{
+			name:      "Noisy",
+			trace:     noisyTrace(2, start, 50, time.Minute, 200*time.Millisecond, 25),
+			total:     10000,
+			wantRate:  50,
+			tolerance: 0.05,
+		},
+		{
+			name:      "Very noisy",
+			trace:     noisyTrace(3, start, 1000, 2*time.Minute, 100*time.Millisecond, 2000),
+			total:     1000000,
+			wantRate:  1000,
+			tolerance: 0.1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := NewKalmanEstimator(1, 25*25)
+			now := observeTrace(k, tt.trace)
+
+			assert.InEpsilon(t, tt.wantRate, k.Rate(), tt.tolerance)
+
+			value := tt.trace[len(tt.trace)-1].value
+			want := time.Duration(float64(tt.total-value) / tt.wantRate * float64(time.Second))
+			got, ok := k.Estimate(now, value, tt.total)
+			assert.True(t, ok)
+			assert.InEpsilon(t, float64(want), float64(got), tt.tolerance)
+		})
+	}
+}
+
+func TestKalmanEstimator_Bursty(t *testing.T) {
+	start := time.Now()
+	k := NewKalmanEstimator(1, 500*500)
+
+	// 100 values per second, delivered in bursts of 500 every 5 seconds and polled 10 times per second
+	for elapsed := 100 * time.Millisecond; elapsed <= 2*time.Minute; elapsed += 100 * time.Millisecond {
+		delivered := int(elapsed/(5*time.Second)) * 500
+		k.Observe(start.Add(elapsed), delivered)
+
+		if elapsed > 30*time.Second {
+			assert.InEpsilon(t, 100, k.Rate(), 0.3, "rate should not overreact to bursts at %s", elapsed)
+		}
+	}
+}
+
+func TestKalmanEstimator_RateChange(t *testing.T) {
+	start := time.Now()
+	k := NewKalmanEstimator(10, 25)
+
+	observeTrace(k, noisyTrace(4, start, 50, time.Minute, 200*time.Millisecond, 5))
+	for _, p := range noisyTrace(5, start.Add(time.Minute), 100, 30*time.Second, 200*time.Millisecond, 5) {
+		k.Observe(p.at, 3000+p.value)
+	}
+	assert.InEpsilon(t, 100, k.Rate(), 0.05, "rate should follow the new speed")
+}
+
+func TestKalmanEstimator_NotEnoughData(t *testing.T) {
+	k := NewKalmanEstimator(0, 0)
+	now := time.Now()
+
+	_, ok := k.Estimate(now, 0, 100)
+	assert.False(t, ok)
+
+	k.Observe(now, 0)
+	_, ok = k.Estimate(now, 0, 100)
+	assert.False(t, ok, "one observation gives no rate")
+
+	k.Observe(now.Add(time.Second), 10)
+	got, ok := k.Estimate(now.Add(time.Second), 10, 100)
+	assert.True(t, ok)
+	assert.InEpsilon(t, float64(9*time.Second), float64(got), 0.05)
+
+	k.Reset()
+	_, ok = k.Estimate(now, 10, 100)
+	assert.False(t, ok)
+}
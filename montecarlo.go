@@ -0,0 +1,193 @@
+package gotimeleft
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// MonteCarloEstimator is an Estimator simulating the remaining items many times by drawing
+	// from the per-item durations observed so far, which gives a distribution of the time left
+	// when items vary widely in cost
+	MonteCarloEstimator struct {
+		mu sync.Mutex
+
+		simulations int
+		sampleSize  int
+		seed        int64
+		seeded      bool
+		rnd         *rand.Rand
+
+		lastTime  time.Time
+		lastValue int
+		durations []monteCarloDuration
+
+		// Distribution computed for cachedRemaining items after cachedCount observations
+		cached          bool
+		cachedRemaining int
+		cachedCount     int
+		distribution    ETADistribution
+	}
+
+	// ETADistribution is the distribution of the time left, as percentiles of the simulations
+	ETADistribution struct {
+		P50 time.Duration
+		P90 time.Duration
+		P99 time.Duration
+	}
+
+	// monteCarloDuration is the mean duration per item, in seconds, of the items completed by one step
+	monteCarloDuration struct {
+		perItem float64
+		items   int
+	}
+)
+
+const (
+	// monteCarloMaxDurations is the number of most recent step durations drawn from
+	monteCarloMaxDurations = 1000
+	// monteCarloSampleSize is the number of items drawn per simulation: beyond it, the deviation
+	// of the drawn items from the mean is scaled up instead, bounding the cost of each estimate
+	monteCarloSampleSize = 100
+)
+
+// NewMonteCarloEstimator creates a Monte Carlo estimator running the given number of
+// simulations per estimate (1000 when not positive), with a random seed
+func NewMonteCarloEstimator(simulations int) *MonteCarloEstimator {
+	if simulations < 1 {
+		simulations = 1000
+	}
+	return &MonteCarloEstimator{
+		simulations: simulations,
+		sampleSize:  monteCarloSampleSize,
+		rnd:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SetSeed makes the simulations deterministic, starting over from the seed on every Reset
+func (m *MonteCarloEstimator) SetSeed(seed int64) *MonteCarloEstimator {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.seed, m.seeded = seed, true
+	m.rnd = rand.New(rand.NewSource(seed))
+	m.cached = false
+	return m
+}
+
+// Observe records the duration per item of the items completed since the previous observation,
+// with their number
+func (m *MonteCarloEstimator) Observe(at time.Time, value int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lastTime.IsZero() || value < m.lastValue {
+		m.lastTime, m.lastValue = at, value
+		return
+	}
+	if value == m.lastValue {
+		return
+	}
+
+	items := value - m.lastValue
+	m.durations = append(m.durations, monteCarloDuration{
+		perItem: at.Sub(m.lastTime).Seconds() / float64(items),
+		items:   items,
+	})
+	if len(m.durations) > monteCarloMaxDurations {
+		m.durations = m.durations[len(m.durations)-monteCarloMaxDurations:]
+	}
+	m.lastTime, m.lastValue = at, value
+	m.cached = false
+}
+
+// Estimate returns the median time left of the simulations
+func (m *MonteCarloEstimator) Estimate(now time.Time, value, total int) (time.Duration, bool) {
+	d, ok := m.Distribution(value, total)
+	return d.P50, ok
+}
+
+// Distribution returns the percentiles of the time left to complete the remaining items,
+// false until two durations were observed
+func (m *MonteCarloEstimator) Distribution(value, total int) (ETADistribution, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if value >= total {
+		return ETADistribution{}, true
+	}
+	if len(m.durations) < 2 {
+		return ETADistribution{}, false
+	}
+
+	remaining := total - value
+	if !m.cached || m.cachedRemaining != remaining || m.cachedCount != len(m.durations) {
+		m.distribution = m.simulate(remaining)
+		m.cached, m.cachedRemaining, m.cachedCount = true, remaining, len(m.durations)
+	}
+	return m.distribution, true
+}
+
+// simulate draws the durations of the remaining items for every simulation. When there are more
+// items than the sample size, a sample is drawn and its deviation from the mean is scaled by
+// √(remaining/sample), which has the same distribution for sums of many items.
+//
+// The mean per item of a step of n items deviates √n times less than a single item, so its
+// deviation is scaled by √n to be drawn as one item.
+func (m *MonteCarloEstimator) simulate(remaining int) ETADistribution {
+	var seconds float64
+	var items int
+	for _, d := range m.durations {
+		seconds += d.perItem * float64(d.items)
+		items += d.items
+	}
+	mean := seconds / float64(items)
+
+	drawn := remaining
+	if drawn > m.sampleSize {
+		drawn = m.sampleSize
+	}
+	scale := math.Sqrt(float64(remaining) / float64(drawn))
+
+	totals := make([]float64, m.simulations)
+	for i := range totals {
+		var deviation float64
+		for j := 0; j < drawn; j++ {
+			d := m.durations[m.rnd.Intn(len(m.durations))]
+			deviation += (d.perItem - mean) * math.Sqrt(float64(d.items))
+		}
+		totals[i] = math.Max(0, float64(remaining)*mean+deviation*scale)
+	}
+	sort.Float64s(totals)
+
+	return ETADistribution{
+		P50: percentileDuration(totals, 0.5),
+		P90: percentileDuration(totals, 0.9),
+		P99: percentileDuration(totals, 0.99),
+	}
+}
+
+// percentileDuration returns the percentile of sorted seconds as a duration
+func percentileDuration(sorted []float64, p float64) time.Duration {
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return time.Duration(sorted[i] * float64(time.Second))
+}
+
+// Reset forgets every observation
+func (m *MonteCarloEstimator) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastTime, m.lastValue = time.Time{}, 0
+	m.durations = nil
+	m.cached = false
+	if m.seeded {
+		m.rnd = rand.New(rand.NewSource(m.seed))
+	}
+}
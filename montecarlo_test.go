@@ -0,0 +1,156 @@
+package gotimeleft
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// observeItems feeds one item per observation, 90% taking a second and 10% taking 20 seconds
+func observeItems(e Estimator, start time.Time, items int) {
+	rnd := rand.New(rand.NewSource(1))
+	at := start
+	e.Observe(at, 0)
+	for i := 1; i <= items; i++ {
+		if rnd.Float64() < 0.1 {
+			at = at.Add(20 * time.Second)
+		} else {
+			at = at.Add(time.Second)
+		}
+		e.Observe(at, i)
+	}
+}
+
+func TestMonteCarloEstimator(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining int
+	}{
+		{name: "Fewer items than the sample", remaining: 50},
+		{name: "Many items", remaining: 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMonteCarloEstimator(0).SetSeed(1)
+			observeItems(m, time.Now(), 1000)
+
+			var mean, variance float64
+			for _, d := range m.durations {
+				mean += d.perItem
+			}
+			mean /= float64(len(m.durations))
+			for _, d := range m.durations {
+				variance += (d.perItem - mean) * (d.perItem - mean)
+			}
+			variance /= float64(len(m.durations))
+
+			d, ok := m.Distribution(1000, 1000+tt.remaining)
+			assert.True(t, ok)
+			assert.True(t, d.P50 < d.P90 && d.P90 < d.P99, "percentiles should be ordered: %+v", d)
+
+			// The sum of many items is close to normal
+			spread := math.Sqrt(variance * float64(tt.remaining))
+			assert.InEpsilon(t, mean*float64(tt.remaining), d.P50.Seconds(), 0.05)
+			assert.InEpsilon(t, 1.2816*spread, (d.P90 - d.P50).Seconds(), 0.25)
+
+			got, ok := m.Estimate(time.Now(), 1000, 1000+tt.remaining)
+			assert.True(t, ok)
+			assert.Equal(t, d.P50, got)
+		})
+	}
+}
+
+func TestMonteCarloEstimator_Batched(t *testing.T) {
+	// The same items as observeItems, ten per observation
+	rnd := rand.New(rand.NewSource(1))
+	durations := make([]float64, 10000)
+	var mean, variance float64
+	for i := range durations {
+		durations[i] = 1
+		if rnd.Float64() < 0.1 {
+			durations[i] = 20
+		}
+		mean += durations[i]
+	}
+	mean /= float64(len(durations))
+	for _, d := range durations {
+		variance += (d - mean) * (d - mean)
+	}
+	variance /= float64(len(durations))
+
+	m := NewMonteCarloEstimator(0).SetSeed(1)
+	at := time.Now()
+	m.Observe(at, 0)
+	for i := 0; i < len(durations); i += 10 {
+		for _, d := range durations[i : i+10] {
+			at = at.Add(time.Duration(d * float64(time.Second)))
+		}
+		m.Observe(at, i+10)
+	}
+
+	remaining := 10000
+	d, ok := m.Distribution(len(durations), len(durations)+remaining)
+	assert.True(t, ok)
+
+	spread := math.Sqrt(variance * float64(remaining))
+	assert.InEpsilon(t, mean*float64(remaining), d.P50.Seconds(), 0.05)
+	assert.InEpsilon(t, 1.2816*spread, (d.P90 - d.P50).Seconds(), 0.25, "batches should not narrow the spread")
+}
+
+func TestMonteCarloEstimator_Rescaled(t *testing.T) {
+	start := time.Now()
+	exact := NewMonteCarloEstimator(2000).SetSeed(2)
+	exact.sampleSize = math.MaxInt32
+	rescaled := NewMonteCarloEstimator(2000).SetSeed(2)
+	observeItems(exact, start, 500)
+	observeItems(rescaled, start, 500)
+
+	want, _ := exact.Distribution(500, 3000)
+	got, _ := rescaled.Distribution(500, 3000)
+	assert.InEpsilon(t, want.P50.Seconds(), got.P50.Seconds(), 0.02)
+	assert.InEpsilon(t, (want.P90 - want.P50).Seconds(), (got.P90 - got.P50).Seconds(), 0.2)
+	assert.InEpsilon(t, (want.P99 - want.P50).Seconds(), (got.P99 - got.P50).Seconds(), 0.3)
+}
+
+func TestMonteCarloEstimator_Seed(t *testing.T) {
+	start := time.Now()
+	a := NewMonteCarloEstimator(100).SetSeed(42)
+	b := NewMonteCarloEstimator(100).SetSeed(42)
+	observeItems(a, start, 200)
+	observeItems(b, start, 200)
+
+	da, _ := a.Distribution(200, 1000)
+	db, _ := b.Distribution(200, 1000)
+	assert.Equal(t, da, db, "the same seed should give the same distribution")
+
+	a.Reset()
+	observeItems(a, start, 200)
+	again, _ := a.Distribution(200, 1000)
+	assert.Equal(t, da, again, "a reset should start over from the seed")
+}
+
+func TestMonteCarloEstimator_NotEnoughData(t *testing.T) {
+	m := NewMonteCarloEstimator(10).SetSeed(1)
+	now := time.Now()
+
+	_, ok := m.Estimate(now, 0, 100)
+	assert.False(t, ok)
+
+	m.Observe(now, 0)
+	m.Observe(now.Add(time.Second), 10)
+	_, ok = m.Estimate(now, 10, 100)
+	assert.False(t, ok, "one duration gives no distribution")
+
+	m.Observe(now.Add(2*time.Second), 20)
+	got, ok := m.Estimate(now, 20, 100)
+	assert.True(t, ok)
+	assert.Equal(t, 8*time.Second, got)
+
+	got, ok = m.Estimate(now, 100, 100)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), got)
+}
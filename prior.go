@@ -0,0 +1,46 @@
+package gotimeleft
+
+import "time"
+
+// SetExpectedRate seeds the estimation with the speed the task is expected to run at, in values
+// per second, so there is an estimate before the first step. The expected speed counts as much
+// as confidence of observed progress: it is blended with the observed speed, which takes over as
+// more time is observed. It is also used while a custom Estimator has no estimate yet.
+// A rate of 0 removes the expected speed.
+func (t *TimeLeft) SetExpectedRate(perSecond float64, confidence time.Duration) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.expectedSpeed = perSecond / 1e6
+	t.expectedConfidence = confidence
+	return t
+}
+
+// priorTimeLeft estimates the time left at the expected speed alone
+func (t *TimeLeft) priorTimeLeft() (time.Duration, bool) {
+	if t.expectedSpeed <= 0 {
+		return 0, false
+	}
+	if t.totalValues > 0 && t.lastValue >= t.totalValues {
+		return 0, true
+	}
+	return time.Duration(float64(t.totalValues-t.lastValue)/t.expectedSpeed) * time.Microsecond, true
+}
+
+// blendPrior weights the expected speed by its confidence and the observed speed by the time it
+// was observed over, both in values per microsecond
+func (t *TimeLeft) blendPrior(observedSpeed float64) float64 {
+	if t.expectedSpeed <= 0 {
+		return observedSpeed
+	}
+
+	observedFor := t.lastStepTime.Sub(t.initializationTime).Seconds()
+	if observedFor < 0 {
+		observedFor = 0
+	}
+	confidence := t.expectedConfidence.Seconds()
+	if observedFor+confidence <= 0 {
+		return observedSpeed
+	}
+	return (t.expectedSpeed*confidence + observedSpeed*observedFor) / (confidence + observedFor)
+}
@@ -0,0 +1,86 @@
+package gotimeleft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeLeft_SetExpectedRate(t *testing.T) {
+	tl := Init(1000)
+	assert.Equal(t, 24*time.Hour, tl.GetTimeLeft(), "no estimate without steps")
+
+	tl.SetExpectedRate(100, 10*time.Second)
+	assert.Equal(t, 10*time.Second, tl.GetTimeLeft(), "the expected rate gives an estimate before the first step")
+
+	s := tl.Snapshot()
+	assert.True(t, s.Estimated)
+	assert.Equal(t, 10*time.Second, s.TimeLeft)
+
+	tl.Reset(500)
+	assert.Equal(t, 5*time.Second, tl.GetTimeLeft(), "the expected rate survives a reset")
+
+	tl.SetExpectedRate(0, 0)
+	assert.Equal(t, 24*time.Hour, tl.GetTimeLeft())
+}
+
+func TestTimeLeft_blendPrior(t *testing.T) {
+	tests := []struct {
+		name       string
+		expected   float64
+		confidence time.Duration
+		observed   float64
+		observedIn time.Duration
+		want       float64
+	}{
+		{
+			name:     "No expected rate",
+			observed: 200, observedIn: 10 * time.Second,
+			want: 200,
+		},
+		{
+			name:     "Nothing observed yet",
+			expected: 100, confidence: 10 * time.Second,
+			observed: 200,
+			want:     100,
+		},
+		{
+			name:     "Equal weights",
+			expected: 100, confidence: 10 * time.Second,
+			observed: 200, observedIn: 10 * time.Second,
+			want: 150,
+		},
+		{
+			name:     "Evidence dominates",
+			expected: 100, confidence: 10 * time.Second,
+			observed: 200, observedIn: 990 * time.Second,
+			want: 199,
+		},
+		{
+			name:     "No confidence",
+			expected: 100,
+			observed: 200, observedIn: time.Second,
+			want: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := Init(1000).SetExpectedRate(tt.expected, tt.confidence)
+			tl.lastStepTime = tl.initializationTime.Add(tt.observedIn)
+
+			assert.InDelta(t, tt.want, tl.blendPrior(tt.observed/1e6)*1e6, 1e-9)
+		})
+	}
+}
+
+func TestTimeLeft_SetExpectedRate_Estimator(t *testing.T) {
+	stub := &stubEstimator{}
+	tl := Init(1000).SetEstimator(stub).SetExpectedRate(100, time.Minute)
+
+	assert.Equal(t, 10*time.Second, tl.GetTimeLeft(), "the expected rate is used until the estimator has an estimate")
+
+	stub.estimate = time.Minute
+	assert.Equal(t, time.Minute, tl.GetTimeLeft())
+}
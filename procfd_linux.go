@@ -0,0 +1,169 @@
+package gotimeleft
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type (
+	// ProcessFile is a regular file opened by a process, with the position of its descriptor
+	ProcessFile struct {
+		FD       int
+		Path     string
+		Position int64
+		Size     int64
+		TimeLeft *TimeLeft
+	}
+
+	// ProcessWatcher polls the files opened by a process and drives one TimeLeft per file
+	ProcessWatcher struct {
+		PID      int
+		Interval time.Duration
+		OnUpdate func(files []ProcessFile)
+
+		trackers map[string]*TimeLeft
+	}
+)
+
+// ScanProcessFiles returns the non-empty regular files opened for reading by a process, read from
+// /proc/<pid>/fd and /proc/<pid>/fdinfo, sorted by descriptor. Files opened write-only are skipped,
+// their position following their size.
+func ScanProcessFiles(pid int) ([]ProcessFile, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	entries, err := os.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]ProcessFile, 0, len(entries))
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// The descriptor may be closed at any time, so skip it on any error
+		link := filepath.Join(dir, "fd", entry.Name())
+		info, err := os.Stat(link)
+		if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
+			continue
+		}
+		path, err := os.Readlink(link)
+		if err != nil {
+			continue
+		}
+		pos, flags, err := readFDInfo(filepath.Join(dir, "fdinfo", entry.Name()))
+		if err != nil || flags&syscall.O_ACCMODE == syscall.O_WRONLY {
+			continue
+		}
+
+		files = append(files, ProcessFile{
+			FD:       fd,
+			Path:     path,
+			Position: pos,
+			Size:     info.Size(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].FD < files[j].FD })
+	return files, nil
+}
+
+// readFDInfo reads the pos and the octal flags fields of a /proc/<pid>/fdinfo/<fd> file
+func readFDInfo(path string) (pos int64, flags int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var hasPos, hasFlags bool
+	scanner := bufio.NewScanner(f)
+	for !(hasPos && hasFlags) && scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "pos:"):
+			if pos, err = strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "pos:")), 10, 64); err != nil {
+				return 0, 0, err
+			}
+			hasPos = true
+		case strings.HasPrefix(line, "flags:"):
+			value, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "flags:")), 8, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+			flags, hasFlags = int(value), true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	if !hasPos || !hasFlags {
+		return 0, 0, errors.New("no position or flags in " + path)
+	}
+	return pos, flags, nil
+}
+
+// Run polls the process every interval until it exits, returning nil, or the context is cancelled
+func (w *ProcessWatcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	w.trackers = make(map[string]*TimeLeft)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		files, err := ScanProcessFiles(w.PID)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		w.update(files)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// update feeds the positions into the trackers, dropping the ones of closed files
+func (w *ProcessWatcher) update(files []ProcessFile) {
+	seen := make(map[string]bool, len(files))
+	for i, f := range files {
+		key := strconv.Itoa(f.FD) + ":" + f.Path
+		seen[key] = true
+
+		t, ok := w.trackers[key]
+		if ok {
+			t.SetTotal(int(f.Size)).Value(int(f.Position))
+		} else {
+			t = Init(int(f.Size)).resume(int(f.Position))
+			w.trackers[key] = t
+		}
+		files[i].TimeLeft = t
+	}
+	for key := range w.trackers {
+		if !seen[key] {
+			delete(w.trackers, key)
+		}
+	}
+
+	if w.OnUpdate != nil {
+		w.OnUpdate(files)
+	}
+}
@@ -0,0 +1,119 @@
+package gotimeleft
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startChildWithFile starts a child process holding a file open with the flag at the given position
+func startChildWithFile(t *testing.T, size, position int64, flag int) (*exec.Cmd, string) {
+	path := filepath.Join(t.TempDir(), "input.dat")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", int(size))), 0o600))
+
+	f, err := os.OpenFile(path, flag, 0)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer f.Close()
+	_, err = f.Seek(position, io.SeekStart)
+	assert.NoError(t, err)
+
+	// The child inherits the open file description, including its position
+	cmd := exec.Command("sleep", "10")
+	cmd.ExtraFiles = []*os.File{f}
+	if err := cmd.Start(); err != nil {
+		t.Skip("cannot start child process:", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return cmd, path
+}
+
+func TestScanProcessFiles(t *testing.T) {
+	cmd, path := startChildWithFile(t, 1000, 250, os.O_RDONLY)
+
+	files, err := ScanProcessFiles(cmd.Process.Pid)
+	assert.NoError(t, err)
+
+	var found *ProcessFile
+	for i := range files {
+		if files[i].Path == path {
+			found = &files[i]
+		}
+	}
+	if assert.NotNil(t, found, "child's open file should be listed") {
+		assert.Equal(t, 3, found.FD)
+		assert.Equal(t, int64(250), found.Position)
+		assert.Equal(t, int64(1000), found.Size)
+	}
+
+	_, err = ScanProcessFiles(-1)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestScanProcessFiles_WriteOnly(t *testing.T) {
+	cmd, path := startChildWithFile(t, 1000, 1000, os.O_WRONLY)
+
+	files, err := ScanProcessFiles(cmd.Process.Pid)
+	assert.NoError(t, err)
+	for _, f := range files {
+		assert.NotEqual(t, path, f.Path, "a file opened write-only is not read progress")
+	}
+}
+
+func TestProcessWatcher_Run(t *testing.T) {
+	cmd, path := startChildWithFile(t, 1000, 500, os.O_RDONLY)
+
+	updates := make(chan []ProcessFile, 10)
+	w := &ProcessWatcher{
+		PID:      cmd.Process.Pid,
+		Interval: 10 * time.Millisecond,
+		OnUpdate: func(files []ProcessFile) {
+			select {
+			case updates <- files:
+			default:
+			}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- w.Run(ctx) }()
+
+	select {
+	case files := <-updates:
+		var tracked *TimeLeft
+		for _, f := range files {
+			if f.Path == path {
+				tracked = f.TimeLeft
+			}
+		}
+		if assert.NotNil(t, tracked) {
+			assert.Equal(t, "500/1000", tracked.GetProgressValues())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an update")
+	}
+
+	// The watcher stops when the process exits
+	cmd.Process.Kill()
+	cmd.Wait()
+	select {
+	case err := <-result:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		cancel()
+		t.Fatal("watcher should stop when the process exits")
+	}
+	cancel()
+}
@@ -0,0 +1,295 @@
+package gotimeleft
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// ProfileStore keeps the progress curves of completed runs in a JSON file, by job name
+	ProfileStore struct {
+		mu      sync.Mutex
+		path    string
+		maxRuns int
+		jobs    map[string][]ProfileRun
+	}
+
+	// ProfileRun is the progress curve of a completed run
+	ProfileRun struct {
+		Total      int           `json:"total"`
+		Duration   time.Duration `json:"duration_ns"`
+		FinishedAt time.Time     `json:"finished_at"`
+		// TimeFractions is the fraction of the duration elapsed when each 5% of the total was
+		// reached, from 0% to 100%
+		TimeFractions []float64 `json:"time_fractions"`
+	}
+
+	// ProfileEstimator is an Estimator predicting the time left from the progress curves of
+	// previous runs of the same job, so jobs that slow down or speed up in known places are
+	// estimated correctly. Without previous runs it uses its fallback estimator.
+	ProfileEstimator struct {
+		mu       sync.Mutex
+		store    *ProfileStore
+		name     string
+		runs     []ProfileRun
+		fallback Estimator
+
+		start  time.Time
+		points []profilePoint
+	}
+
+	// profilePoint is a value reached at seconds since the start of the run
+	profilePoint struct {
+		elapsed float64
+		value   int
+	}
+
+	// profileFile is the JSON representation of a ProfileStore
+	profileFile struct {
+		Jobs map[string][]ProfileRun `json:"jobs"`
+	}
+)
+
+const (
+	// profileCurvePoints is the number of points of a stored curve, one every 5% of the total
+	profileCurvePoints = 21
+	// profileMaxPoints bounds the observations kept during a run, halving their resolution when reached
+	profileMaxPoints = 4096
+	// profilePacePrior is the share of a previous run's duration counted as evidence that the
+	// current run goes at the same pace, so the first observations do not swing the estimate
+	profilePacePrior = 0.05
+)
+
+// ErrIncompleteRun is returned when saving a run that has not reached its total
+var ErrIncompleteRun = errors.New("run has not reached its total")
+
+// OpenProfileStore loads the profiles from the file at path, starting empty when it does not
+// exist yet. The last maxRuns runs of each job are kept (10 when not positive).
+func OpenProfileStore(path string, maxRuns int) (*ProfileStore, error) {
+	if maxRuns < 1 {
+		maxRuns = 10
+	}
+	s := &ProfileStore{
+		path:    path,
+		maxRuns: maxRuns,
+		jobs:    make(map[string][]ProfileRun),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file profileFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	for name, runs := range file.Jobs {
+		s.jobs[name] = runs
+	}
+	return s, nil
+}
+
+// Runs returns the stored runs of the job, oldest first
+func (s *ProfileStore) Runs(name string) []ProfileRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]ProfileRun(nil), s.jobs[name]...)
+}
+
+// Add stores a completed run of the job, dropping the oldest runs beyond the limit, and writes the file
+func (s *ProfileStore) Add(name string, run ProfileRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := append(s.jobs[name], run)
+	if len(runs) > s.maxRuns {
+		runs = runs[len(runs)-s.maxRuns:]
+	}
+	s.jobs[name] = runs
+	return s.write()
+}
+
+// write replaces the file atomically, so a crash never leaves it half written
+func (s *ProfileStore) write() error {
+	data, err := json.MarshalIndent(profileFile{Jobs: s.jobs}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Estimator creates an estimator for a new run of the job, based on its stored runs. The
+// fallback is used while the job has no stored runs, a linear regression over the last 30
+// observations when nil.
+func (s *ProfileStore) Estimator(name string, fallback Estimator) *ProfileEstimator {
+	if fallback == nil {
+		fallback = NewRegressionEstimator(30, 0)
+	}
+	return &ProfileEstimator{
+		store:    s,
+		name:     name,
+		runs:     s.Runs(name),
+		fallback: fallback,
+	}
+}
+
+// Observe records the value reached at the given time
+func (p *ProfileEstimator) Observe(at time.Time, value int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.fallback.Observe(at, value)
+
+	if p.start.IsZero() {
+		p.start = at
+	}
+	if n := len(p.points); n > 0 && p.points[n-1].value == value {
+		return
+	}
+	p.points = append(p.points, profilePoint{elapsed: at.Sub(p.start).Seconds(), value: value})
+
+	if len(p.points) >= profileMaxPoints {
+		// Keep every other point, and always the last one
+		kept := p.points[:0]
+		for i := 0; i < len(p.points); i += 2 {
+			kept = append(kept, p.points[i])
+		}
+		if last := p.points[len(p.points)-1]; kept[len(kept)-1] != last {
+			kept = append(kept, last)
+		}
+		p.points = kept
+	}
+}
+
+// Estimate returns the median of the time left predicted by each previous run, its duration
+// scaled to the total and to the pace of the current run so far
+func (p *ProfileEstimator) Estimate(now time.Time, value, total int) (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.runs) == 0 || total <= 0 || p.start.IsZero() {
+		return p.fallback.Estimate(now, value, total)
+	}
+	if value >= total {
+		return 0, true
+	}
+
+	fraction := math.Max(0, float64(value)/float64(total))
+	elapsed := now.Sub(p.start).Seconds()
+
+	var predictions []float64
+	for _, run := range p.runs {
+		if run.Total <= 0 || len(run.TimeFractions) < 2 {
+			continue
+		}
+		duration := run.Duration.Seconds() * float64(total) / float64(run.Total)
+		done := interpolateCurve(run.TimeFractions, fraction)
+		prior := profilePacePrior * duration
+		pace := (elapsed + prior) / (done*duration + prior)
+		predictions = append(predictions, pace*duration*(1-done))
+	}
+	if len(predictions) == 0 {
+		return p.fallback.Estimate(now, value, total)
+	}
+	return time.Duration(medianOf(predictions) * float64(time.Second)), true
+}
+
+// Save stores the observed run, which must have reached total, as a previous run of the job
+func (p *ProfileEstimator) Save(total int) error {
+	p.mu.Lock()
+	curve, duration, ok := buildCurve(p.points, total)
+	p.mu.Unlock()
+
+	if !ok {
+		return ErrIncompleteRun
+	}
+	return p.store.Add(p.name, ProfileRun{
+		Total:         total,
+		Duration:      time.Duration(duration * float64(time.Second)),
+		FinishedAt:    time.Now(),
+		TimeFractions: curve,
+	})
+}
+
+// rebase restarts the fallback, keeping the observations of the current run for Save
+func (p *ProfileEstimator) rebase() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.fallback.Reset()
+}
+
+// Reset forgets the current run, keeping the previous runs
+func (p *ProfileEstimator) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.fallback.Reset()
+	p.start = time.Time{}
+	p.points = nil
+}
+
+// buildCurve returns the fractions of the duration elapsed when each 5% of the total was
+// reached, interpolating between observations, and the duration until the total was reached
+func buildCurve(points []profilePoint, total int) ([]float64, float64, bool) {
+	if total <= 0 || len(points) == 0 || points[len(points)-1].value < total {
+		return nil, 0, false
+	}
+
+	times := make([]float64, profileCurvePoints)
+	for k := range times {
+		target := float64(total) * float64(k) / float64(profileCurvePoints-1)
+		j := sort.Search(len(points), func(i int) bool { return float64(points[i].value) >= target })
+		if j == 0 {
+			times[k] = points[0].elapsed
+			continue
+		}
+		before, after := points[j-1], points[j]
+		share := (target - float64(before.value)) / float64(after.value-before.value)
+		times[k] = before.elapsed + share*(after.elapsed-before.elapsed)
+	}
+
+	duration := times[len(times)-1]
+	if duration <= 0 {
+		return nil, 0, false
+	}
+	for k := range times {
+		times[k] /= duration
+	}
+	return times, duration, true
+}
+
+// interpolateCurve returns the fraction of the duration elapsed at the given fraction of the total
+func interpolateCurve(curve []float64, fraction float64) float64 {
+	position := math.Min(fraction, 1) * float64(len(curve)-1)
+	i := int(position)
+	if i >= len(curve)-1 {
+		return curve[len(curve)-1]
+	}
+	return curve[i] + (curve[i+1]-curve[i])*(position-float64(i))
+}
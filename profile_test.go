@@ -0,0 +1,168 @@
+package gotimeleft
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// observeCurve feeds a run where value(t) = total * (t/duration)^power, sampled every second
+func observeCurve(e Estimator, start time.Time, total int, duration time.Duration, power float64) {
+	for elapsed := time.Duration(0); elapsed <= duration; elapsed += time.Second {
+		e.Observe(start.Add(elapsed), int(float64(total)*math.Pow(elapsed.Seconds()/duration.Seconds(), power)))
+	}
+}
+
+func TestProfileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+
+	store, err := OpenProfileStore(path, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, store.Runs("nightly"))
+
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, store.Add("nightly", ProfileRun{Total: i, Duration: time.Minute, TimeFractions: []float64{0, 1}}))
+	}
+	assert.NoError(t, store.Add("weekly", ProfileRun{Total: 10, Duration: time.Hour, TimeFractions: []float64{0, 1}}))
+
+	reopened, err := OpenProfileStore(path, 2)
+	assert.NoError(t, err)
+	runs := reopened.Runs("nightly")
+	if assert.Len(t, runs, 2, "only the last runs should be kept") {
+		assert.Equal(t, 2, runs[0].Total)
+		assert.Equal(t, 3, runs[1].Total)
+		assert.Equal(t, time.Minute, runs[1].Duration)
+	}
+	assert.Len(t, reopened.Runs("weekly"), 1)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file should be left")
+
+	assert.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, err = OpenProfileStore(path, 0)
+	assert.Error(t, err)
+}
+
+func TestProfileEstimator(t *testing.T) {
+	store, err := OpenProfileStore(filepath.Join(t.TempDir(), "profiles.json"), 0)
+	assert.NoError(t, err)
+
+	// A job slowing down at the start: value(t) = total * (t/duration)²
+	start := time.Now()
+	first := store.Estimator("index", nil)
+	observeCurve(first, start, 10000, 100*time.Second, 2)
+	assert.NoError(t, first.Save(10000))
+
+	tests := []struct {
+		name   string
+		total  int
+		pace   float64
+		wantAt float64
+	}{
+		{name: "Same run", total: 10000, pace: 1, wantAt: 50},
+		{name: "Twice as slow", total: 10000, pace: 2, wantAt: 100},
+		{name: "Twice as large", total: 20000, pace: 1, wantAt: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := store.Estimator("index", nil)
+			duration := time.Duration(tt.pace * float64(tt.total) / 10000 * float64(100*time.Second))
+			observeCurve(e, start, tt.total, duration/2, 2)
+
+			// Half the duration in, a quarter of the total is done
+			now := start.Add(duration / 2)
+			value := tt.total / 4
+			got, ok := e.Estimate(now, value, tt.total)
+			assert.True(t, ok)
+			assert.InEpsilon(t, tt.wantAt, got.Seconds(), 0.05)
+		})
+	}
+}
+
+func TestProfileEstimator_Fallback(t *testing.T) {
+	store, err := OpenProfileStore(filepath.Join(t.TempDir(), "profiles.json"), 0)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	e := store.Estimator("new job", nil)
+	observeCurve(e, start, 1000, 10*time.Second, 1)
+
+	// No previous run: the live speed of 100 values per second is used
+	got, ok := e.Estimate(start.Add(10*time.Second), 1000, 2000)
+	assert.True(t, ok)
+	assert.InEpsilon(t, 10, got.Seconds(), 0.01)
+
+	assert.ErrorIs(t, e.Save(2000), ErrIncompleteRun)
+	assert.Empty(t, store.Runs("new job"))
+}
+
+func TestProfileEstimator_TimeLeft(t *testing.T) {
+	store, err := OpenProfileStore(filepath.Join(t.TempDir(), "profiles.json"), 0)
+	assert.NoError(t, err)
+
+	e := store.Estimator("job", nil)
+	tl := Init(100).SetEstimator(e)
+	tl.Value(50)
+	tl.Value(100)
+	assert.NoError(t, e.Save(100))
+	assert.Len(t, store.Runs("job"), 1)
+
+	tl.SetEstimator(store.Estimator("job", nil)).Reset(100)
+	assert.Less(t, tl.GetTimeLeft(), time.Second, "the previous run was almost instant")
+}
+
+func TestProfileEstimator_SpeedChange(t *testing.T) {
+	store, err := OpenProfileStore(filepath.Join(t.TempDir(), "profiles.json"), 0)
+	assert.NoError(t, err)
+
+	e := store.Estimator("job", nil)
+	tl := Init(1000).SetEstimator(e).SetChangeDetection(0, DefaultChangeThreshold)
+
+	// Feed steps with synthetic timing: 100 values per second for 5s, then 10 for 50s
+	advance := func(values int, elapsed time.Duration) {
+		tl.update(func() {
+			tl.lastValue += values
+			tl.lastStepTime = tl.lastStepTime.Add(elapsed)
+		})
+	}
+	for i := 0; i < 50; i++ {
+		advance(10, 100*time.Millisecond)
+	}
+	for i := 0; i < 50; i++ {
+		advance(10, time.Second)
+	}
+	assert.False(t, tl.SpeedChangedAt().IsZero(), "the slowdown should be detected")
+
+	assert.NoError(t, e.Save(1000))
+	runs := store.Runs("job")
+	if assert.Len(t, runs, 1) {
+		assert.Equal(t, 55*time.Second, runs[0].Duration.Round(time.Millisecond), "the whole run should be kept")
+		assert.InDelta(t, 5.0/55, runs[0].TimeFractions[10], 1e-6, "half the total was reached after 5s")
+	}
+}
+
+func TestBuildCurve(t *testing.T) {
+	points := []profilePoint{{0, 0}, {10, 50}, {30, 100}}
+
+	curve, duration, ok := buildCurve(points, 100)
+	assert.True(t, ok)
+	assert.Equal(t, 30.0, duration)
+	assert.Len(t, curve, profileCurvePoints)
+	assert.Equal(t, 0.0, curve[0])
+	assert.InDelta(t, 1.0/3, curve[10], 1e-9, "half the total was reached a third of the way")
+	assert.InDelta(t, 2.0/3, curve[15], 1e-9)
+	assert.Equal(t, 1.0, curve[20])
+
+	assert.InDelta(t, 14.0/30, interpolateCurve(curve, 0.6), 1e-9)
+	assert.InDelta(t, 14.5/30, interpolateCurve(curve, 0.6125), 1e-9, "between two points of the curve")
+	assert.Equal(t, 1.0, interpolateCurve(curve, 2))
+
+	_, _, ok = buildCurve(points, 200)
+	assert.False(t, ok)
+}
@@ -0,0 +1,80 @@
+package gotimeleft
+
+import (
+	"bufio"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// prometheusMetrics are the gauges exported for every registered TimeLeft
+var prometheusMetrics = []struct {
+	name  string
+	help  string
+	value func(s Snapshot) float64
+}{
+	{"gotimeleft_value", "Current progress value.", func(s Snapshot) float64 {
+		return float64(s.Value)
+	}},
+	{"gotimeleft_total", "Total value to complete the task.", func(s Snapshot) float64 {
+		return float64(s.Total)
+	}},
+	{"gotimeleft_fraction", "Progress as a fraction from 0 to 1.", func(s Snapshot) float64 {
+		return s.Fraction
+	}},
+	{"gotimeleft_rate_per_second", "Current speed in values per second.", func(s Snapshot) float64 {
+		return s.PerSecond
+	}},
+	{"gotimeleft_eta_seconds", "Estimated time left in seconds, NaN when unknown.", func(s Snapshot) float64 {
+		if !s.Estimated {
+			return math.NaN()
+		}
+		return s.TimeLeft.Seconds()
+	}},
+	{"gotimeleft_elapsed_seconds", "Time spent since initialization in seconds.", func(s Snapshot) float64 {
+		return s.Elapsed.Seconds()
+	}},
+	{"gotimeleft_stalled", "1 if no progress arrived within the stall threshold.", func(s Snapshot) float64 {
+		if s.Status == StatusStalled {
+			return 1
+		}
+		return 0
+	}},
+}
+
+// labelEscaper escapes label values for the Prometheus text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// PrometheusHandler returns an http.Handler that renders the registered trackers
+// in the Prometheus text exposition format, with a name label per tracker
+func PrometheusHandler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		snapshots := r.Snapshots()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		bw := bufio.NewWriter(w)
+		for _, metric := range prometheusMetrics {
+			bw.WriteString("# HELP " + metric.name + " " + metric.help + "\n")
+			bw.WriteString("# TYPE " + metric.name + " gauge\n")
+			for _, s := range snapshots {
+				bw.WriteString(metric.name + `{name="` + labelEscaper.Replace(s.Name) + `"} `)
+				bw.WriteString(formatPrometheusValue(metric.value(s.Snapshot)) + "\n")
+			}
+		}
+		bw.Flush()
+	})
+}
+
+// formatPrometheusValue formats a sample value, including the special values
+func formatPrometheusValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
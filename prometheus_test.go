@@ -0,0 +1,45 @@
+package gotimeleft
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	r := NewRegistry()
+	r.Register("copy", newTestTimeLeft(100, 50, 0.00001))
+	r.Register(`c:\"quoted"`, Init(10))
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler(r).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "version=0.0.4")
+
+	body, _ := io.ReadAll(rec.Body)
+	got := string(body)
+
+	for _, want := range []string{
+		"# TYPE gotimeleft_value gauge\n",
+		`gotimeleft_value{name="copy"} 50` + "\n",
+		`gotimeleft_total{name="copy"} 100` + "\n",
+		`gotimeleft_fraction{name="copy"} 0.5` + "\n",
+		`gotimeleft_rate_per_second{name="copy"} 10` + "\n",
+		`gotimeleft_eta_seconds{name="c:\\\"quoted\""} NaN` + "\n",
+		`gotimeleft_stalled{name="copy"} 0` + "\n",
+		`gotimeleft_elapsed_seconds{name="copy"} `,
+	} {
+		assert.Contains(t, got, want)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(got), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		assert.Len(t, strings.Fields(line), 2, "malformed sample line %q", line)
+	}
+}
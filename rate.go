@@ -0,0 +1,68 @@
+package gotimeleft
+
+import (
+	"math"
+	"time"
+)
+
+type (
+	// RateRequirement describes the speed needed to complete the task by a target time
+	RateRequirement struct {
+		Target             time.Time
+		Remaining          int
+		Available          time.Duration
+		RequiredPerSecond  float64
+		CurrentPerSecond   float64
+		ShortfallPerSecond float64
+		Workers            int
+	}
+)
+
+// RequiredRate returns the speed needed to complete the task by the target time, the shortfall
+// versus the current speed and the number of parallel workers needed assuming linear scaling.
+// The required rate is +Inf when the target has already passed with work remaining.
+func (t *TimeLeft) RequiredRate(target time.Time) RateRequirement {
+	t.mu.Lock()
+	remaining := t.totalValues - t.lastValue
+	current := t.perSecond()
+	t.mu.Unlock()
+
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	r := RateRequirement{
+		Target:           target,
+		Remaining:        remaining,
+		Available:        time.Until(target),
+		CurrentPerSecond: current,
+	}
+
+	switch {
+	case remaining == 0:
+		return r
+	case r.Available <= 0:
+		r.RequiredPerSecond = math.Inf(1)
+	default:
+		r.RequiredPerSecond = float64(remaining) / r.Available.Seconds()
+	}
+
+	if r.RequiredPerSecond > current {
+		r.ShortfallPerSecond = r.RequiredPerSecond - current
+	}
+	if current > 0 && !math.IsInf(r.RequiredPerSecond, 1) {
+		r.Workers = int(math.Ceil(r.RequiredPerSecond / current))
+	}
+
+	return r
+}
+
+// Feasible returns true if the target can still be met, given enough workers
+func (r RateRequirement) Feasible() bool {
+	return r.Remaining == 0 || r.Available > 0
+}
+
+// OnTrack returns true if the current speed is enough to meet the target
+func (r RateRequirement) OnTrack() bool {
+	return r.Feasible() && r.ShortfallPerSecond == 0
+}
@@ -0,0 +1,81 @@
+package gotimeleft
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeLeft_RequiredRate(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		timeLeft *TimeLeft
+		target   time.Duration
+		checker  func(got RateRequirement)
+	}{
+		{
+			name:     "On track",
+			timeLeft: newTestTimeLeft(1000, 0, 0.00001), // 10/s
+			target:   1000 * time.Second,
+			checker: func(got RateRequirement) {
+				assert.Equal(t, 1000, got.Remaining)
+				assert.InDelta(t, 1.0, got.RequiredPerSecond, 0.01)
+				assert.InDelta(t, 10.0, got.CurrentPerSecond, 0.0001)
+				assert.Equal(t, 0.0, got.ShortfallPerSecond)
+				assert.Equal(t, 1, got.Workers)
+				assert.True(t, got.OnTrack())
+			},
+		},
+		{
+			name:     "Needs more workers",
+			timeLeft: newTestTimeLeft(1000, 0, 0.00001), // 10/s
+			target:   40 * time.Second,
+			checker: func(got RateRequirement) {
+				assert.InDelta(t, 25.0, got.RequiredPerSecond, 0.01)
+				assert.InDelta(t, 15.0, got.ShortfallPerSecond, 0.01)
+				assert.Equal(t, 3, got.Workers)
+				assert.True(t, got.Feasible())
+				assert.False(t, got.OnTrack())
+			},
+		},
+		{
+			name:     "No speed yet",
+			timeLeft: Init(1000),
+			target:   100 * time.Second,
+			checker: func(got RateRequirement) {
+				assert.InDelta(t, 10.0, got.RequiredPerSecond, 0.01)
+				assert.InDelta(t, 10.0, got.ShortfallPerSecond, 0.01)
+				assert.Equal(t, 0, got.Workers)
+			},
+		},
+		{
+			name:     "Target passed",
+			timeLeft: newTestTimeLeft(1000, 500, 0.00001),
+			target:   -time.Second,
+			checker: func(got RateRequirement) {
+				assert.True(t, math.IsInf(got.RequiredPerSecond, 1))
+				assert.Equal(t, 0, got.Workers)
+				assert.False(t, got.Feasible())
+			},
+		},
+		{
+			name:     "Completed",
+			timeLeft: newTestTimeLeft(1000, 1000, 0.00001),
+			target:   -time.Second,
+			checker: func(got RateRequirement) {
+				assert.Equal(t, 0, got.Remaining)
+				assert.Equal(t, 0.0, got.RequiredPerSecond)
+				assert.True(t, got.OnTrack())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.checker(tt.timeLeft.RequiredRate(time.Now().Add(tt.target)))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package gotimeleft
+
+import "io"
+
+type (
+	// Reader is an io.Reader that steps a TimeLeft by the number of bytes read
+	Reader struct {
+		r io.Reader
+		t *TimeLeft
+	}
+)
+
+// NewReader wraps r so every read steps t by the number of bytes read
+func NewReader(r io.Reader, t *TimeLeft) *Reader {
+	return &Reader{r: r, t: t}
+}
+
+// Read reads from the underlying reader and steps the TimeLeft
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.t.Step(n)
+	}
+	return n, err
+}
+
+// TimeLeft returns the TimeLeft stepped by the reader
+func (r *Reader) TimeLeft() *TimeLeft {
+	return r.t
+}
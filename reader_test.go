@@ -0,0 +1,57 @@
+package gotimeleft
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReader(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		input     string
+		total     int
+		wantValue int
+		wantDone  bool
+	}{
+		{
+			name:      "Known size",
+			input:     strings.Repeat("x", 10000),
+			total:     10000,
+			wantValue: 10000,
+			wantDone:  true,
+		},
+		{
+			name:      "Unknown size",
+			input:     strings.Repeat("x", 10000),
+			total:     0,
+			wantValue: 10000,
+			wantDone:  false,
+		},
+		{
+			name:      "Larger than expected",
+			input:     strings.Repeat("x", 10000),
+			total:     100,
+			wantValue: 100,
+			wantDone:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(strings.NewReader(tt.input), Init(tt.total))
+
+			var out bytes.Buffer
+			n, err := io.Copy(&out, r)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(tt.input)), n, "all data should pass through")
+			assert.Equal(t, tt.input, out.String())
+			assert.Equal(t, tt.wantValue, r.TimeLeft().GetValue())
+			assert.Equal(t, tt.wantDone, r.TimeLeft().IsDone())
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package gotimeleft
+
+import (
+	"sort"
+	"sync"
+)
+
+type (
+	// Registry holds named TimeLeft instances for reporting
+	Registry struct {
+		mu       sync.RWMutex
+		trackers map[string]*TimeLeft
+	}
+
+	// NamedSnapshot is a Snapshot of a registered TimeLeft
+	NamedSnapshot struct {
+		Name string
+		Snapshot
+	}
+)
+
+// NewRegistry creates a new empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		trackers: make(map[string]*TimeLeft),
+	}
+}
+
+// Register adds a TimeLeft under the given name, replacing any previous one
+func (r *Registry) Register(name string, t *TimeLeft) *TimeLeft {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.trackers[name] = t
+	return t
+}
+
+// Unregister removes the TimeLeft with the given name
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.trackers, name)
+}
+
+// Get returns the TimeLeft with the given name, or nil
+func (r *Registry) Get(name string) *TimeLeft {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.trackers[name]
+}
+
+// Names returns the registered names in sorted order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.trackers))
+	for name := range r.trackers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Snapshots returns a Snapshot of every registered TimeLeft, sorted by name
+func (r *Registry) Snapshots() []NamedSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := make([]NamedSnapshot, 0, len(r.trackers))
+	for name, t := range r.trackers {
+		snapshots = append(snapshots, NamedSnapshot{Name: name, Snapshot: t.Snapshot()})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots
+}
@@ -0,0 +1,27 @@
+package gotimeleft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	copying := r.Register("copy", Init(100).Step(10))
+	r.Register("archive", Init(10))
+
+	assert.Equal(t, []string{"archive", "copy"}, r.Names())
+	assert.Same(t, copying, r.Get("copy"))
+	assert.Nil(t, r.Get("missing"))
+
+	snapshots := r.Snapshots()
+	if assert.Len(t, snapshots, 2) {
+		assert.Equal(t, "archive", snapshots[0].Name)
+		assert.Equal(t, "copy", snapshots[1].Name)
+		assert.Equal(t, 10, snapshots[1].Value)
+	}
+
+	r.Unregister("archive")
+	assert.Equal(t, []string{"copy"}, r.Names())
+}
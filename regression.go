@@ -0,0 +1,149 @@
+package gotimeleft
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// RegressionEstimator is an Estimator fitting a least-squares line over a sliding window of
+	// timestamped values, which is not thrown off by unevenly spaced observations
+	RegressionEstimator struct {
+		mu sync.Mutex
+
+		window   int
+		halfLife time.Duration
+
+		origin  time.Time
+		samples []regressionSample
+
+		fitted    bool
+		slope     float64
+		intercept float64
+		r2        float64
+	}
+
+	// regressionSample is an observed value, at seconds since the origin
+	regressionSample struct {
+		x, y float64
+	}
+)
+
+// NewRegressionEstimator creates a linear regression estimator over the last window observations
+// (30 when not positive). With a positive halfLife, the weight of an observation halves for every
+// halfLife it is older than the newest one.
+func NewRegressionEstimator(window int, halfLife time.Duration) *RegressionEstimator {
+	if window < 1 {
+		window = 30
+	}
+	return &RegressionEstimator{
+		window:   window,
+		halfLife: halfLife,
+	}
+}
+
+// Observe adds the value to the window and fits the line again
+func (r *RegressionEstimator) Observe(at time.Time, value int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.origin.IsZero() {
+		r.origin = at
+	}
+	r.samples = append(r.samples, regressionSample{x: at.Sub(r.origin).Seconds(), y: float64(value)})
+	if len(r.samples) > r.window {
+		r.samples = r.samples[len(r.samples)-r.window:]
+	}
+	r.fit()
+}
+
+// fit computes the weighted least-squares line of the samples, centering them for numerical stability
+func (r *RegressionEstimator) fit() {
+	r.fitted = false
+	if len(r.samples) < 2 {
+		return
+	}
+
+	newest := r.samples[len(r.samples)-1].x
+	weights := make([]float64, len(r.samples))
+	var sumW, meanX, meanY float64
+	for i, s := range r.samples {
+		weights[i] = 1
+		if r.halfLife > 0 {
+			weights[i] = math.Exp2(-(newest - s.x) / r.halfLife.Seconds())
+		}
+		sumW += weights[i]
+		meanX += weights[i] * s.x
+		meanY += weights[i] * s.y
+	}
+	meanX /= sumW
+	meanY /= sumW
+
+	var sxx, sxy, syy float64
+	for i, s := range r.samples {
+		dx, dy := s.x-meanX, s.y-meanY
+		sxx += weights[i] * dx * dx
+		sxy += weights[i] * dx * dy
+		syy += weights[i] * dy * dy
+	}
+	if sxx == 0 {
+		return
+	}
+
+	r.fitted = true
+	r.slope = sxy / sxx
+	r.intercept = meanY - r.slope*meanX
+	r.r2 = 1
+	if syy > 0 {
+		r.r2 = sxy * sxy / (sxx * syy)
+	}
+}
+
+// Estimate returns the time left until the fitted line reaches total
+func (r *RegressionEstimator) Estimate(now time.Time, value, total int) (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.fitted || r.slope <= 0 {
+		return 0, false
+	}
+
+	reached := (float64(total) - r.intercept) / r.slope
+	left := reached - now.Sub(r.origin).Seconds()
+	if left < 0 {
+		left = 0
+	}
+	return time.Duration(left * float64(time.Second)), true
+}
+
+// Rate returns the slope of the fitted line in values per second
+func (r *RegressionEstimator) Rate() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.slope
+}
+
+// R2 returns the coefficient of determination of the fit, from 0 (no linear progress)
+// to 1 (perfectly linear), to judge how reliable the estimate is
+func (r *RegressionEstimator) R2() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.fitted {
+		return 0
+	}
+	return r.r2
+}
+
+// Reset forgets every observation
+func (r *RegressionEstimator) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.origin = time.Time{}
+	r.samples = nil
+	r.fitted = false
+	r.slope, r.intercept, r.r2 = 0, 0, 0
+}
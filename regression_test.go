@@ -0,0 +1,112 @@
+package gotimeleft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegressionEstimator(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name      string
+		trace     []tracePoint
+		total     int
+		wantRate  float64
+		tolerance float64
+		minR2     float64
+		maxR2     float64
+	}{
+		{
+			name:      "Uneven spacing",
+			trace:     noisyTrace(1, start, 20, time.Minute, time.Second, 0),
+			total:     5000,
+			wantRate:  20,
+			tolerance: 0.01,
+			minR2:     0.999,
+			maxR2:     1,
+		},
+		{
+			name:      "Noisy",
+			trace:     noisyTrace(2, start, 20, time.Minute, time.Second, 150),
+			total:     5000,
+			wantRate:  20,
+			tolerance: 0.1,
+			minR2:     0.5,
+			maxR2:     0.99,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegressionEstimator(100, 0)
+			now := observeTrace(r, tt.trace)
+
+			assert.InEpsilon(t, tt.wantRate, r.Rate(), tt.tolerance)
+			assert.GreaterOrEqual(t, r.R2(), tt.minR2)
+			assert.LessOrEqual(t, r.R2(), tt.maxR2)
+
+			// The true line reaches the total at total/rate seconds from the start
+			want := start.Add(time.Duration(float64(tt.total) / tt.wantRate * float64(time.Second))).Sub(now)
+			got, ok := r.Estimate(now, tt.trace[len(tt.trace)-1].value, tt.total)
+			assert.True(t, ok)
+			assert.InEpsilon(t, float64(want), float64(got), tt.tolerance)
+		})
+	}
+}
+
+func TestRegressionEstimator_Window(t *testing.T) {
+	start := time.Now()
+	r := NewRegressionEstimator(10, 0)
+
+	// Slow phase followed by a fast phase longer than the window
+	observeTrace(r, noisyTrace(3, start, 10, time.Minute, time.Second, 0))
+	for _, p := range noisyTrace(4, start.Add(time.Minute), 50, 20*time.Second, time.Second, 0) {
+		r.Observe(p.at, 600+p.value)
+	}
+	assert.InEpsilon(t, 50, r.Rate(), 0.01, "only the last window should be fitted")
+}
+
+func TestRegressionEstimator_RecencyWeight(t *testing.T) {
+	start := time.Now()
+	unweighted := NewRegressionEstimator(100, 0)
+	weighted := NewRegressionEstimator(100, 2*time.Second)
+
+	for _, r := range []*RegressionEstimator{unweighted, weighted} {
+		observeTrace(r, noisyTrace(5, start, 10, time.Minute, time.Second, 0))
+		for _, p := range noisyTrace(6, start.Add(time.Minute), 50, 10*time.Second, time.Second, 0) {
+			r.Observe(p.at, 600+p.value)
+		}
+	}
+
+	assert.Less(t, unweighted.Rate(), 25.0, "unweighted fit should mostly see the slow phase")
+	assert.Greater(t, weighted.Rate(), 40.0, "recency weighted fit should follow the fast phase")
+}
+
+func TestRegressionEstimator_NotEnoughData(t *testing.T) {
+	now := time.Now()
+	r := NewRegressionEstimator(0, 0)
+
+	r.Observe(now, 0)
+	_, ok := r.Estimate(now, 0, 100)
+	assert.False(t, ok)
+	assert.Equal(t, 0.0, r.R2())
+
+	r.Observe(now, 0)
+	_, ok = r.Estimate(now, 0, 100)
+	assert.False(t, ok, "observations at the same instant give no slope")
+
+	r.Observe(now.Add(time.Second), 0)
+	_, ok = r.Estimate(now.Add(time.Second), 0, 100)
+	assert.False(t, ok, "no progress gives no estimate")
+
+	r.Reset()
+	r.Observe(now, 0)
+	r.Observe(now.Add(time.Second), 10)
+	got, ok := r.Estimate(now.Add(time.Second), 10, 100)
+	assert.True(t, ok)
+	assert.Equal(t, 9*time.Second, got)
+	assert.Equal(t, 1.0, r.R2())
+}
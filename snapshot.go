@@ -0,0 +1,93 @@
+package gotimeleft
+
+import "time"
+
+type (
+	// Status describes the state of a task
+	Status string
+
+	// Snapshot is an immutable view of a TimeLeft captured at a single instant
+	Snapshot struct {
+		Value      int
+		Total      int
+		Fraction   float64
+		PerSecond  float64
+		TimeLeft   time.Duration
+		Estimated  bool
+		Elapsed    time.Duration
+		Status     Status
+		StartedAt  time.Time
+		LastStepAt time.Time
+		TakenAt    time.Time
+		// SpeedChangedAt is when the last change of the speed was detected, zero if none was
+		SpeedChangedAt time.Time
+	}
+)
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusStalled Status = "stalled"
+	StatusDone    Status = "done"
+)
+
+// Snapshot returns the current state of all values at once, so they are consistent with each other
+func (t *TimeLeft) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	s := Snapshot{
+		Value:      t.lastValue,
+		Total:      t.totalValues,
+		PerSecond:  t.perSecond(),
+		Elapsed:    now.Sub(t.initializationTime),
+		StartedAt:  t.initializationTime,
+		LastStepAt: t.lastStepTime,
+		TakenAt:    now,
+
+		SpeedChangedAt: t.speedChangedAt,
+	}
+	s.Fraction = progressFraction(t.lastValue, t.totalValues)
+
+	switch {
+	case t.finished:
+		s.Status = StatusDone
+		s.Estimated = true
+	case t.isStalled(now):
+		s.Status = StatusStalled
+	case t.lastValue == 0 && t.speedPerMicrosecond == 0:
+		s.Status = StatusPending
+	default:
+		s.Status = StatusRunning
+	}
+
+	if s.Status != StatusDone {
+		s.TimeLeft, s.Estimated = t.timeLeft()
+		if !s.Estimated {
+			s.TimeLeft = 24 * time.Hour // Same default as GetTimeLeft
+		}
+	}
+
+	return s
+}
+
+// ETA returns the estimated completion time
+func (s Snapshot) ETA() time.Time {
+	return s.TakenAt.Add(s.TimeLeft)
+}
+
+// ProgressValues returns the progress as a string (10/100)
+func (s Snapshot) ProgressValues() string {
+	return formatProgressValues(s.Value, s.Total)
+}
+
+// ProgressBar returns a string representation of the progress bar
+func (s Snapshot) ProgressBar(fullBar int) string {
+	return formatProgressBar(s.Fraction, fullBar)
+}
+
+// Progress returns the progress as a string (10.1% 15.5%), 0% when the total is unknown like Fraction
+func (s Snapshot) Progress(prec int) string {
+	return formatPercent(s.Fraction, prec)
+}
@@ -0,0 +1,106 @@
+package gotimeleft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeLeft_Snapshot(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		setupFunc func() *TimeLeft
+		checker   func(got Snapshot)
+	}{
+		{
+			name: "Pending",
+			setupFunc: func() *TimeLeft {
+				return Init(100)
+			},
+			checker: func(got Snapshot) {
+				assert.Equal(t, StatusPending, got.Status)
+				assert.False(t, got.Estimated)
+				assert.Equal(t, 24*time.Hour, got.TimeLeft)
+				assert.Equal(t, "[..............................]", got.ProgressBar(30))
+			},
+		},
+		{
+			name: "Running",
+			setupFunc: func() *TimeLeft {
+				return newTestTimeLeft(100, 50, 0.00001) // 10/s
+			},
+			checker: func(got Snapshot) {
+				assert.Equal(t, StatusRunning, got.Status)
+				assert.Equal(t, 50, got.Value)
+				assert.Equal(t, 100, got.Total)
+				assert.Equal(t, 0.5, got.Fraction)
+				assert.InDelta(t, 10.0, got.PerSecond, 0.0001)
+				assert.True(t, got.Estimated)
+				assert.InDelta(t, float64(5*time.Second), float64(got.TimeLeft), float64(time.Millisecond))
+				assert.Equal(t, got.TakenAt.Add(got.TimeLeft), got.ETA())
+				assert.Equal(t, "50/100", got.ProgressValues())
+				assert.Equal(t, "50.0%", got.Progress(1))
+				assert.Equal(t, "[==============>...............]", got.ProgressBar(30))
+			},
+		},
+		{
+			name: "Stalled",
+			setupFunc: func() *TimeLeft {
+				tl := newTestTimeLeft(100, 50, 0.00001).SetStallTimeout(time.Second)
+				tl.lastProgressTime = time.Now().Add(-time.Minute)
+				return tl
+			},
+			checker: func(got Snapshot) {
+				assert.Equal(t, StatusStalled, got.Status)
+			},
+		},
+		{
+			name: "Done",
+			setupFunc: func() *TimeLeft {
+				return Init(100).Step(50).Step(50)
+			},
+			checker: func(got Snapshot) {
+				assert.Equal(t, StatusDone, got.Status)
+				assert.Equal(t, 1.0, got.Fraction)
+				assert.Equal(t, time.Duration(0), got.TimeLeft)
+				assert.Equal(t, "100%", got.Progress(0))
+			},
+		},
+		{
+			name: "Unknown total",
+			setupFunc: func() *TimeLeft {
+				return newTestTimeLeft(0, 50, 0.00001)
+			},
+			checker: func(got Snapshot) {
+				assert.Equal(t, 0.0, got.Fraction)
+				assert.Equal(t, "0.0%", got.Progress(1))
+				assert.False(t, got.Estimated, "an unknown total should give no estimate")
+				assert.Equal(t, 24*time.Hour, got.TimeLeft)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.checker(tt.setupFunc().Snapshot())
+		})
+	}
+}
+
+func TestTimeLeft_SnapshotReadOnly(t *testing.T) {
+	tl := Init(1000)
+	for i := 0; i < 5; i++ {
+		time.Sleep(time.Millisecond)
+		tl.Step(10)
+	}
+	history := append([]float64(nil), tl.speedHistory...)
+	times := append([]time.Time(nil), tl.speedTimes...)
+
+	first := tl.Snapshot()
+	second := tl.Snapshot()
+	assert.Equal(t, first.TimeLeft, second.TimeLeft, "back-to-back snapshots should agree")
+	assert.Equal(t, history, tl.speedHistory, "a snapshot should not change the history")
+	assert.Equal(t, times, tl.speedTimes)
+}
@@ -0,0 +1,133 @@
+package gotimeleft
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// SpeedFilter selects how outliers are removed from the speed history before it is averaged
+type SpeedFilter int
+
+const (
+	// FilterStdDev keeps the speeds within 1.5 standard deviations of the mean (default)
+	FilterStdDev SpeedFilter = iota
+	// FilterMAD keeps the speeds within 3 scaled median absolute deviations of the median,
+	// which a few extreme speeds cannot drag
+	FilterMAD
+	// FilterTrimmed drops the lowest and highest 10% of the speeds
+	FilterTrimmed
+)
+
+const (
+	// madScale makes the median absolute deviation comparable to a standard deviation
+	madScale = 1.4826
+	// trimFraction is the share of speeds dropped at each end by FilterTrimmed
+	trimFraction = 0.1
+	// halfLifeHistory is the number of half-lives a speed is kept, after which it weighs under 3%
+	halfLifeHistory = 5
+	// maxTimedHistorySize bounds the speeds kept with a half-life, for very frequent steps
+	maxTimedHistorySize = 10000
+)
+
+// SetSpeedFilter sets how outliers are removed from the speed history
+func (t *TimeLeft) SetSpeedFilter(filter SpeedFilter) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.speedFilter = filter
+	return t
+}
+
+// SetHalfLife weights the speed history by age in wall-clock time, a speed measured halfLife
+// ago counting half as much as the latest one, however often Step is called. The history then
+// holds the speed of each step for five half-lives instead of the last 30 running speeds. Zero
+// restores the default weighting by position in the history.
+func (t *TimeLeft) SetHalfLife(halfLife time.Duration) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.halfLife = halfLife
+	return t
+}
+
+// filterBounds returns the range of speeds that are not outliers, and the central value to
+// fall back on when too few speeds are in range
+func (t *TimeLeft) filterBounds(speeds []float64) (lower, upper, center float64) {
+	switch t.speedFilter {
+	case FilterMAD:
+		return madBounds(speeds)
+	case FilterTrimmed:
+		return trimmedBounds(speeds)
+	default:
+		return stdDevBounds(speeds)
+	}
+}
+
+// stdDevBounds keeps the speeds within 1.5 standard deviations of the mean
+func stdDevBounds(speeds []float64) (lower, upper, center float64) {
+	mean, stdDev := meanStdDev(speeds)
+	return mean - 1.5*stdDev, mean + 1.5*stdDev, mean
+}
+
+// madBounds keeps the speeds within 3 scaled median absolute deviations of the median
+func madBounds(speeds []float64) (lower, upper, center float64) {
+	median := medianOf(speeds)
+
+	deviations := make([]float64, len(speeds))
+	for i, s := range speeds {
+		deviations[i] = math.Abs(s - median)
+	}
+	spread := madScale * medianOf(deviations)
+	if spread == 0 {
+		// More than half of the speeds are equal, use the mean deviation so the others are not all dropped
+		var sum float64
+		for _, d := range deviations {
+			sum += d
+		}
+		spread = math.Sqrt(math.Pi/2) * sum / float64(len(deviations))
+	}
+	return median - 3*spread, median + 3*spread, median
+}
+
+// trimmedBounds drops the lowest and highest speeds
+func trimmedBounds(speeds []float64) (lower, upper, center float64) {
+	sorted := sortedCopy(speeds)
+	trim := int(float64(len(sorted)) * trimFraction)
+	if trim == 0 && len(sorted) > 3 {
+		trim = 1
+	}
+	return sorted[trim], sorted[len(sorted)-1-trim], medianOf(speeds)
+}
+
+// meanStdDev returns the mean and the population standard deviation, computed in two passes
+// so that float rounding cannot make the variance negative
+func meanStdDev(values []float64) (mean, stdDev float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	var sumSq float64
+	for _, v := range values {
+		sumSq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sumSq / float64(len(values)))
+}
+
+// medianOf returns the median of the values without reordering them
+func medianOf(values []float64) float64 {
+	sorted := sortedCopy(values)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return sorted[middle-1] + (sorted[middle]-sorted[middle-1])/2
+	}
+	return sorted[middle]
+}
+
+// sortedCopy returns the values sorted in ascending order
+func sortedCopy(values []float64) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted
+}
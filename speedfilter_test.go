@@ -0,0 +1,201 @@
+package gotimeleft
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// speedSamples is a random speed history around a random base speed, for property-based tests
+type speedSamples []float64
+
+func (speedSamples) Generate(rnd *rand.Rand, size int) reflect.Value {
+	base := math.Pow(10, rnd.Float64()*12-6)
+	samples := make(speedSamples, 3+rnd.Intn(28))
+	for i := range samples {
+		samples[i] = base * (0.9 + 0.2*rnd.Float64())
+	}
+	return reflect.ValueOf(samples)
+}
+
+var speedFilters = []SpeedFilter{FilterStdDev, FilterMAD, FilterTrimmed}
+
+// averageSpeed feeds the speeds to a new tracker using the filter and returns the last average
+func averageSpeed(filter SpeedFilter, speeds []float64) float64 {
+	tl := Init(100).SetSpeedFilter(filter)
+
+	now := time.Now()
+	for i, s := range speeds {
+		tl.recordSpeed(s, now.Add(time.Duration(i)*time.Second))
+	}
+	return tl.calculateAverageSpeed()
+}
+
+func TestSpeedFilter_WithinRange(t *testing.T) {
+	for _, filter := range speedFilters {
+		property := func(speeds speedSamples) bool {
+			average := averageSpeed(filter, speeds)
+			sorted := sortedCopy(speeds)
+			return !math.IsNaN(average) && average >= sorted[0] && average <= sorted[len(sorted)-1]
+		}
+		assert.NoError(t, quick.Check(property, nil), "filter %d", filter)
+	}
+}
+
+func TestSpeedFilter_IgnoresOutlier(t *testing.T) {
+	for _, filter := range []SpeedFilter{FilterMAD, FilterTrimmed} {
+		property := func(speeds speedSamples, position uint8, factor uint16) bool {
+			if len(speeds) < 10 {
+				return true
+			}
+			sorted := sortedCopy(speeds)
+
+			// Replace one speed by a value far from the others, in either direction
+			outlier := sorted[len(sorted)-1] * (10 + float64(factor))
+			if factor%2 == 0 {
+				outlier = sorted[0] / (10 + float64(factor))
+			}
+			withOutlier := append([]float64(nil), speeds...)
+			withOutlier[int(position)%len(withOutlier)] = outlier
+
+			average := averageSpeed(filter, withOutlier)
+			return average >= sorted[0] && average <= sorted[len(sorted)-1]
+		}
+		assert.NoError(t, quick.Check(property, nil), "filter %d", filter)
+	}
+}
+
+func TestSpeedFilter_StableVariance(t *testing.T) {
+	// Identical speeds make sumSq/n - mean² negative from rounding, and its square root NaN
+	for _, speed := range []float64{0.1, 1.1, 1e8 + 0.1} {
+		speeds := make([]float64, 30)
+		for i := range speeds {
+			speeds[i] = speed
+		}
+
+		mean, stdDev := meanStdDev(speeds)
+		assert.InEpsilon(t, speed, mean, 1e-12)
+		assert.InDelta(t, 0, stdDev, speed*1e-12)
+
+		for _, filter := range speedFilters {
+			assert.InEpsilon(t, speed, averageSpeed(filter, speeds), 1e-12, "filter %d", filter)
+		}
+	}
+}
+
+func TestSpeedFilter_Dragged(t *testing.T) {
+	// A burst of extreme speeds inflates the standard deviation so much that none is considered an outlier
+	speeds := []float64{100, 101, 99, 5000, 5000, 5000, 5000, 102, 98, 100}
+
+	assert.Greater(t, averageSpeed(FilterStdDev, speeds), 1000.0)
+	assert.InDelta(t, 100, averageSpeed(FilterMAD, speeds), 2)
+}
+
+func TestMedianOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{name: "Single", values: []float64{3}, want: 3},
+		{name: "Odd", values: []float64{5, 1, 3}, want: 3},
+		{name: "Even", values: []float64{4, 1, 3, 2}, want: 2.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := append([]float64(nil), tt.values...)
+			assert.Equal(t, tt.want, medianOf(values))
+			assert.Equal(t, tt.values, values, "values should not be reordered")
+		})
+	}
+}
+
+func TestTimeLeft_SetHalfLife(t *testing.T) {
+	start := time.Now()
+
+	// A burst of slow speeds ten minutes ago, then as many fast speeds a second apart
+	feed := func(tl *TimeLeft) float64 {
+		for i := 0; i < 15; i++ {
+			tl.recordSpeed(10, start.Add(time.Duration(i)*10*time.Millisecond))
+		}
+		for i := 0; i < 15; i++ {
+			tl.recordSpeed(20, start.Add(10*time.Minute+time.Duration(i)*time.Second))
+		}
+		return tl.calculateAverageSpeed()
+	}
+
+	byPosition := feed(Init(100))
+	byAge := feed(Init(100).SetHalfLife(10 * time.Second))
+
+	assert.Less(t, byPosition, 17.0, "position weights barely favour recent speeds")
+	assert.InDelta(t, 20, byAge, 0.01, "speeds ten minutes old should be negligible")
+
+	// Without timestamps the position weights are used
+	tl := Init(100).SetHalfLife(10 * time.Second)
+	for i := 0; i < 15; i++ {
+		tl.speedHistory = append(tl.speedHistory, 10)
+	}
+	for i := 0; i < 15; i++ {
+		tl.speedHistory = append(tl.speedHistory, 20)
+	}
+	assert.InDelta(t, byPosition, tl.calculateAverageSpeed(), 1e-9)
+}
+
+func TestTimeLeft_SetHalfLifeStepRate(t *testing.T) {
+	// A speed rising from 10 to 20 over a minute, measured every 10ms or every second
+	feed := func(every time.Duration) float64 {
+		tl := Init(100).SetHalfLife(10 * time.Second)
+		start := time.Now()
+		for elapsed := every; elapsed <= time.Minute; elapsed += every {
+			tl.recordSpeed(10+elapsed.Seconds()/6, start.Add(elapsed))
+		}
+		return tl.calculateAverageSpeed()
+	}
+
+	frequent, sparse := feed(10*time.Millisecond), feed(time.Second)
+	assert.InDelta(t, sparse, frequent, 0.3, "the step rate should not change the average")
+	assert.Less(t, frequent, 19.0, "the speeds of the last seconds should not be the only ones")
+}
+
+func TestTimeLeft_HalfLifeRawSpeeds(t *testing.T) {
+	tl := Init(1000).SetHalfLife(time.Minute)
+	tl.Step(10)
+	time.Sleep(5 * time.Millisecond)
+	tl.Step(10)
+	time.Sleep(5 * time.Millisecond)
+	tl.Step(0)
+
+	if assert.Len(t, tl.speedHistory, 3) {
+		assert.Equal(t, 0.0, tl.speedHistory[2], "the speed of the step, not the running speed")
+	}
+}
+
+func TestTimeLeft_SpeedHistoryPerStep(t *testing.T) {
+	tl := Init(1000)
+	tl.Step(1)
+	for i := 0; i < 5; i++ {
+		time.Sleep(time.Millisecond)
+		tl.Step(10)
+	}
+	assert.Len(t, tl.speedHistory, 6, "one speed per step")
+
+	// Reading the time left many times does not fill the history
+	stepTime := tl.lastStepTime
+	history := append([]float64(nil), tl.speedHistory...)
+	for i := 0; i < 100; i++ {
+		tl.GetTimeLeft()
+	}
+	assert.Equal(t, history, tl.speedHistory)
+	if assert.Len(t, tl.speedTimes, 6) {
+		assert.Equal(t, stepTime, tl.speedTimes[5], "speeds are timed when measured")
+	}
+
+	tl.Value(100)
+	assert.Len(t, tl.speedHistory, 7)
+}
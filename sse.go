@@ -0,0 +1,117 @@
+package gotimeleft
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// SSEHandler returns an http.Handler that streams Snapshot events of the TimeLeft as Server-Sent Events.
+// A "progress" event is sent on connect and every interval, a "milestone" event when the progress
+// crosses one of the milestones (fractions from 0 to 1, every 10% by default) and a final "complete"
+// event when the task is done. Slow clients never block Step or Value.
+func SSEHandler(t *TimeLeft, interval time.Duration, milestones ...float64) http.Handler {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if len(milestones) == 0 {
+		for i := 1; i < 10; i++ {
+			milestones = append(milestones, float64(i)/10)
+		}
+	}
+	milestones = append([]float64(nil), milestones...)
+	sort.Float64s(milestones)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		updates, unsubscribe := t.subscribe()
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+
+		send := func(event string, s Snapshot) bool {
+			data, err := json.Marshal(s)
+			if err != nil {
+				return false
+			}
+			if _, err := w.Write([]byte("event: " + event + "\ndata: " + string(data) + "\n\n")); err != nil {
+				return false
+			}
+			flusher.Flush()
+			return true
+		}
+
+		// Skip the milestones already crossed before the client connected
+		s := t.Snapshot()
+		next := sort.Search(len(milestones), func(i int) bool { return milestones[i] > s.Fraction })
+		if !send("progress", s) {
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-req.Context().Done():
+				return
+			case <-t.Done():
+				send("complete", t.Snapshot())
+				return
+			case <-ticker.C:
+				if !send("progress", t.Snapshot()) {
+					return
+				}
+			case <-updates:
+				s := t.Snapshot()
+				if next >= len(milestones) || s.Fraction < milestones[next] {
+					continue
+				}
+				for next < len(milestones) && s.Fraction >= milestones[next] {
+					next++
+				}
+				if !send("milestone", s) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// subscribe returns a channel that receives a signal after every progress update.
+// Signals are coalesced, so a slow reader never blocks the update.
+func (t *TimeLeft) subscribe() (<-chan struct{}, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if t.subscribers == nil {
+		t.subscribers = make(map[chan struct{}]struct{})
+	}
+	t.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		delete(t.subscribers, ch)
+	}
+}
+
+// notifySubscribers signals every subscriber without blocking
+func (t *TimeLeft) notifySubscribers() {
+	for ch := range t.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package gotimeleft
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sseEvent struct {
+	name  string
+	value int
+}
+
+// readSSEEvents parses the event stream and sends each event to the channel
+func readSSEEvents(t *testing.T, res *http.Response, events chan<- sseEvent) {
+	defer close(events)
+
+	var event sseEvent
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			var data struct {
+				Value int `json:"value"`
+			}
+			assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &data))
+			event.value = data.Value
+		case line == "":
+			events <- event
+			event = sseEvent{}
+		}
+	}
+}
+
+func TestSSEHandler(t *testing.T) {
+	tl := Init(100)
+	server := httptest.NewServer(SSEHandler(tl, time.Hour, 0.5, 0.25))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	events := make(chan sseEvent)
+	go readSSEEvents(t, res, events)
+
+	next := func() sseEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+			return sseEvent{}
+		}
+	}
+
+	assert.Equal(t, sseEvent{"progress", 0}, next())
+
+	tl.Step(10) // No milestone crossed
+	tl.Step(20)
+	assert.Equal(t, sseEvent{"milestone", 30}, next())
+
+	tl.Step(40)
+	assert.Equal(t, sseEvent{"milestone", 70}, next())
+
+	tl.Step(30)
+	assert.Equal(t, sseEvent{"complete", 100}, next())
+
+	_, open := <-events
+	assert.False(t, open, "stream should end after the complete event")
+}
+
+func TestSSEHandler_Disconnect(t *testing.T) {
+	tl := Init(100)
+	server := httptest.NewServer(SSEHandler(tl, 5*time.Millisecond))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	res, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		tl.mu.Lock()
+		defer tl.mu.Unlock()
+		return len(tl.subscribers) == 0
+	}, time.Second, 5*time.Millisecond, "handler should unsubscribe when the client disconnects")
+
+	// Updates never block without readers
+	for i := 0; i < 100; i++ {
+		tl.Step(1)
+	}
+}
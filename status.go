@@ -0,0 +1,62 @@
+package gotimeleft
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// statusResponse is the body served by StatusHandler
+type statusResponse struct {
+	Trackers []NamedSnapshot `json:"trackers"`
+}
+
+// StatusHandler returns an http.Handler that serves the state of the registered trackers as JSON.
+// The name query parameter, repeated or comma separated, filters the trackers by name.
+func StatusHandler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		var names []string
+		for _, value := range req.URL.Query()["name"] {
+			for _, name := range strings.Split(value, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+		}
+
+		res := statusResponse{Trackers: r.Snapshots()}
+		if len(names) > 0 {
+			res.Trackers = filterSnapshots(res.Trackers, names)
+			if len(res.Trackers) == 0 {
+				http.Error(w, "no tracker found", http.StatusNotFound)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// filterSnapshots keeps the snapshots whose name is in names
+func filterSnapshots(snapshots []NamedSnapshot, names []string) []NamedSnapshot {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	filtered := snapshots[:0]
+	for _, s := range snapshots {
+		if wanted[s.Name] {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,117 @@
+package gotimeleft
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusHandler(t *testing.T) {
+	r := NewRegistry()
+	r.Register("copy", newTestTimeLeft(100, 50, 0.00001)) // 5s left
+	r.Register("archive", Init(10))
+	r.Register("index", Init(10).Step(10))
+
+	mux := http.NewServeMux()
+	mux.Handle("/progress", StatusHandler(r))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	type tracker struct {
+		Name     string  `json:"name"`
+		Value    int     `json:"value"`
+		Status   string  `json:"status"`
+		TimeLeft string  `json:"time_left"`
+		ETA      string  `json:"eta"`
+		Seconds  float64 `json:"time_left_seconds"`
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		method     string
+		wantCode   int
+		wantNames  []string
+		checkFirst func(got tracker)
+	}{
+		{
+			name:      "All trackers",
+			wantCode:  http.StatusOK,
+			wantNames: []string{"archive", "copy", "index"},
+		},
+		{
+			name:      "Filter by name",
+			query:     "?name=copy",
+			wantCode:  http.StatusOK,
+			wantNames: []string{"copy"},
+			checkFirst: func(got tracker) {
+				assert.Equal(t, 50, got.Value)
+				assert.Equal(t, "running", got.Status)
+				assert.Equal(t, "5s", got.TimeLeft)
+				eta, err := time.Parse(time.RFC3339, got.ETA)
+				assert.NoError(t, err)
+				assert.WithinDuration(t, time.Now().Add(5*time.Second), eta, 2*time.Second)
+			},
+		},
+		{
+			name:      "Filter by several names",
+			query:     "?name=index,missing&name=archive",
+			wantCode:  http.StatusOK,
+			wantNames: []string{"archive", "index"},
+			checkFirst: func(got tracker) {
+				assert.Equal(t, "pending", got.Status)
+				assert.Empty(t, got.TimeLeft)
+				assert.Empty(t, got.ETA)
+			},
+		},
+		{
+			name:     "Unknown name",
+			query:    "?name=missing",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Wrong method",
+			method:   http.MethodPost,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, _ := http.NewRequest(method, server.URL+"/progress"+tt.query, nil)
+			res, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.wantCode, res.StatusCode)
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+			var body struct {
+				Trackers []tracker `json:"trackers"`
+			}
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+
+			var names []string
+			for _, tr := range body.Trackers {
+				names = append(names, tr.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			if tt.checkFirst != nil {
+				tt.checkFirst(body.Trackers[0])
+			}
+		})
+	}
+}
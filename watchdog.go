@@ -0,0 +1,159 @@
+package gotimeleft
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// minGapSamples is the number of inter-step gaps needed before the learned gap is trusted
+const minGapSamples = 3
+
+// SetStallTimeout flags the tracker as stalled when no progress arrives for the given duration
+func (t *TimeLeft) SetStallTimeout(timeout time.Duration) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stallTimeout = timeout
+	return t
+}
+
+// SetStallGapFactor flags the tracker as stalled when no progress arrives for factor times
+// the typical (median) gap between steps. Once enough gaps are learned it takes precedence
+// over the stall timeout.
+func (t *TimeLeft) SetStallGapFactor(factor float64) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stallGapFactor = factor
+	return t
+}
+
+// OnStall sets the callback invoked by the watchdog when the tracker becomes stalled
+func (t *TimeLeft) OnStall(fn func(stalledFor time.Duration)) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onStall = fn
+	return t
+}
+
+// OnRecover sets the callback invoked when progress arrives on a stalled tracker
+func (t *TimeLeft) OnRecover(fn func(stalledFor time.Duration)) *TimeLeft {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onRecover = fn
+	return t
+}
+
+// IsStalled returns true if no progress has arrived within the stall threshold
+func (t *TimeLeft) IsStalled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.isStalled(time.Now())
+}
+
+// StalledFor returns the time since the last progress if the tracker is stalled, otherwise 0
+func (t *TimeLeft) StalledFor() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if !t.isStalled(now) {
+		return 0
+	}
+	return now.Sub(t.lastProgressTime)
+}
+
+// StartWatchdog checks the tracker every interval and invokes OnStall when it becomes stalled.
+// The returned function stops the watchdog.
+func (t *TimeLeft) StartWatchdog(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				t.checkStall(now)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// checkStall marks the tracker as stalled and invokes OnStall on the transition
+func (t *TimeLeft) checkStall(now time.Time) {
+	t.mu.Lock()
+	if t.stalled || !t.isStalled(now) {
+		t.mu.Unlock()
+		return
+	}
+	t.stalled = true
+	onStall, stalledFor := t.onStall, now.Sub(t.lastProgressTime)
+	t.mu.Unlock()
+
+	if onStall != nil {
+		onStall(stalledFor)
+	}
+}
+
+// isStalled reports whether the silence since the last step exceeds the stall threshold
+func (t *TimeLeft) isStalled(now time.Time) bool {
+	if t.totalValues > 0 && t.lastValue >= t.totalValues {
+		return false
+	}
+
+	threshold := t.stallThreshold()
+	if threshold <= 0 || t.lastProgressTime.IsZero() {
+		return false
+	}
+	return now.Sub(t.lastProgressTime) >= threshold
+}
+
+// stallThreshold returns the silence after which the tracker is considered stalled
+func (t *TimeLeft) stallThreshold() time.Duration {
+	if t.stallGapFactor > 0 && len(t.stepGaps) >= minGapSamples {
+		return time.Duration(float64(t.typicalGap()) * t.stallGapFactor)
+	}
+	return t.stallTimeout
+}
+
+// recordGap adds the time between two steps to the gap history
+func (t *TimeLeft) recordGap(gap time.Duration) {
+	limit := t.maxHistorySize
+	if limit < 1 {
+		limit = 30
+	}
+
+	t.stepGaps = append(t.stepGaps, gap)
+	if len(t.stepGaps) > limit {
+		t.stepGaps = t.stepGaps[len(t.stepGaps)-limit:]
+	}
+}
+
+// typicalGap returns the median gap between steps
+func (t *TimeLeft) typicalGap() time.Duration {
+	gaps := make([]time.Duration, len(t.stepGaps))
+	copy(gaps, t.stepGaps)
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+
+	middle := len(gaps) / 2
+	if len(gaps)%2 == 0 {
+		return (gaps[middle-1] + gaps[middle]) / 2
+	}
+	return gaps[middle]
+}
@@ -0,0 +1,128 @@
+package gotimeleft
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeLeft_IsStalled(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		setupFunc func() *TimeLeft
+		want      bool
+	}{
+		{
+			name: "No threshold configured",
+			setupFunc: func() *TimeLeft {
+				tl := Init(100)
+				tl.lastProgressTime = time.Now().Add(-1 * time.Hour)
+				return tl
+			},
+			want: false,
+		},
+		{
+			name: "Timeout exceeded",
+			setupFunc: func() *TimeLeft {
+				tl := Init(100).SetStallTimeout(time.Second)
+				tl.lastProgressTime = time.Now().Add(-2 * time.Second)
+				return tl
+			},
+			want: true,
+		},
+		{
+			name: "Timeout not exceeded",
+			setupFunc: func() *TimeLeft {
+				tl := Init(100).SetStallTimeout(time.Minute)
+				tl.lastProgressTime = time.Now().Add(-2 * time.Second)
+				return tl
+			},
+			want: false,
+		},
+		{
+			name: "Learned gap exceeded",
+			setupFunc: func() *TimeLeft {
+				tl := Init(100).SetStallTimeout(time.Hour).SetStallGapFactor(3)
+				tl.stepGaps = []time.Duration{time.Second, time.Second, 2 * time.Second}
+				tl.lastProgressTime = time.Now().Add(-4 * time.Second)
+				return tl
+			},
+			want: true,
+		},
+		{
+			name: "Unchanged value reported",
+			setupFunc: func() *TimeLeft {
+				tl := Init(100).SetStallTimeout(time.Second)
+				tl.Value(10)
+				tl.lastProgressTime = time.Now().Add(-2 * time.Second)
+				tl.Value(10)
+				return tl
+			},
+			want: true,
+		},
+		{
+			name: "Completed tracker",
+			setupFunc: func() *TimeLeft {
+				tl := Init(100).SetStallTimeout(time.Second)
+				tl.lastValue = 100
+				tl.lastProgressTime = time.Now().Add(-2 * time.Second)
+				return tl
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := tt.setupFunc()
+			assert.Equal(t, tt.want, tl.IsStalled())
+			if tt.want {
+				assert.Greater(t, tl.StalledFor(), time.Duration(0))
+			} else {
+				assert.Equal(t, time.Duration(0), tl.StalledFor())
+			}
+		})
+	}
+}
+
+func TestTimeLeft_UnchangedValueGaps(t *testing.T) {
+	tl := Init(100)
+	tl.Value(10)
+	for i := 0; i < 5; i++ {
+		tl.Value(10)
+		tl.Step(0)
+	}
+	assert.Len(t, tl.stepGaps, 1, "only changes of the value should be gaps")
+
+	tl.Value(20)
+	assert.Len(t, tl.stepGaps, 2)
+}
+
+func TestTimeLeft_StartWatchdog(t *testing.T) {
+	var stalls, recoveries int32
+
+	tl := Init(100).
+		SetStallTimeout(20 * time.Millisecond).
+		OnStall(func(stalledFor time.Duration) {
+			atomic.AddInt32(&stalls, 1)
+		}).
+		OnRecover(func(stalledFor time.Duration) {
+			assert.GreaterOrEqual(t, stalledFor, 20*time.Millisecond)
+			atomic.AddInt32(&recoveries, 1)
+		})
+
+	stop := tl.StartWatchdog(5 * time.Millisecond)
+	defer stop()
+
+	tl.Step(1)
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, tl.IsStalled())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stalls), "OnStall should fire once per stall")
+
+	tl.Step(1)
+	assert.False(t, tl.IsStalled())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&recoveries))
+}